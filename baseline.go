@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/terakoya76/modd/report"
+)
+
+// defaultBaselinePath represents the baseline file generated when no path is configured.
+const defaultBaselinePath = "modd-baseline.yaml"
+
+// runBaseline regenerates the baseline file accepting every current unmonitored resource.
+// The existing suppressions are kept as they are, and the expired ones are reported to be renewed or resolved.
+func runBaseline(args []string, stdout, stderr io.Writer) int {
+	var cf commonFlags
+	fs := flag.NewFlagSet("baseline", flag.ContinueOnError)
+	cf.register(fs)
+	cf.registerScan(fs)
+	cf.registerBaseline(fs)
+	integrations := fs.String("integrations", "", "comma-separated integrations to be scanned, e.g. aws_rds,aws_sqs (all by default)")
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	its, err := parseIntegrations(*integrations)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	path := cfg.Baseline
+	if path == "" {
+		path = defaultBaselinePath
+	}
+
+	previous, err := report.LoadBaseline(path, true)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	scan, err := runScanner(ctx, cfg, its, cf.concurrency, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	// the previous suppressions of the metrics failed to be evaluated are kept by GenerateBaseline
+	code := 0
+	if len(scan.Errors) > 0 {
+		fmt.Fprintf(stderr, "kept the previous suppressions of %d metrics failed to be evaluated\n", len(scan.Errors))
+		code = cfg.Exit.Codes.PartialFailure
+	}
+
+	baseline := report.GenerateBaseline(scan, previous)
+	now := time.Now()
+	for _, s := range baseline.Suppressions {
+		if s.Expired(now) {
+			fmt.Fprintf(stderr, "suppression of %s for %s expired on %s\n", s.Resource, s.Metric, s.Expires)
+		}
+	}
+
+	if err := writeBaseline(path, baseline); err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	fmt.Fprintf(stdout, "wrote %d suppressions to %s\n", len(baseline.Suppressions), path)
+	return code
+}
+
+// writeBaseline writes the baseline into a temporary file and renames it to the path,
+// so that the existing baseline is kept when the baseline fails to be written.
+func writeBaseline(path string, baseline *report.Baseline) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create baseline file: %w", err)
+	}
+	// the temporary file does not exist anymore once renamed
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if err := report.WriteBaseline(f, baseline); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+
+	if err := f.Chmod(0o644); err != nil { //nolint:gosec // the baseline is supposed to be committed and shared
+		_ = f.Close()
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close baseline file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace baseline file: %w", err)
+	}
+
+	return nil
+}
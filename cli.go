@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
+)
+
+// commonFlags holds the flags shared by the commands.
+// Each command registers only the flags it honours.
+type commonFlags struct {
+	configPath  string
+	format      string
+	pretty      bool
+	regions     string
+	concurrency int
+	timeout     time.Duration
+	baseline    string
+	history     string
+	publish     bool
+
+	scanning bool
+}
+
+// register registers the flag shared by every command.
+func (f *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.configPath, "config", "", "path to the configuration file, e.g. modd.yaml")
+}
+
+// registerFormat registers the flags of the output format.
+func (f *commonFlags) registerFormat(fs *flag.FlagSet) {
+	fs.StringVar(&f.format, "format", "", "output format, overrides output.format of the configuration")
+	f.registerPretty(fs)
+}
+
+// registerPretty registers the flag of the JSON output.
+func (f *commonFlags) registerPretty(fs *flag.FlagSet) {
+	fs.BoolVar(&f.pretty, "pretty", false, "pretty-print the output")
+}
+
+// registerScan registers the flags of the commands which scan resources.
+func (f *commonFlags) registerScan(fs *flag.FlagSet) {
+	f.scanning = true
+	fs.StringVar(&f.regions, "regions", "", "comma-separated AWS regions to be scanned, overrides the configured regions")
+	fs.IntVar(&f.concurrency, "concurrency", 10, "maximum number of concurrent requests to Datadog and evaluations of metrics")
+	fs.DurationVar(&f.timeout, "timeout", 0, "timeout of the whole command, e.g. 5m (no timeout by default)")
+}
+
+// registerBaseline registers the flag of the baseline file.
+func (f *commonFlags) registerBaseline(fs *flag.FlagSet) {
+	fs.StringVar(&f.baseline, "baseline", "", "path to the baseline file, overrides baseline of the configuration")
+}
+
+// registerHistory registers the flag of the scan history database.
+func (f *commonFlags) registerHistory(fs *flag.FlagSet) {
+	fs.StringVar(&f.history, "history", "", "path to the scan history database, overrides history of the configuration")
+}
+
+// registerPublish registers the flag of publishing the scan result.
+func (f *commonFlags) registerPublish(fs *flag.FlagSet) {
+	fs.BoolVar(&f.publish, "publish", false, "publish the scan result to Datadog as custom metrics and events")
+}
+
+// load loads the configuration overridden with the flags.
+func (f *commonFlags) load() (*config.Config, error) {
+	cfg, err := config.Load(f.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if f.format != "" {
+		if err := config.ValidateOutputFormat(f.format); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		cfg.Output.Format = f.format
+	}
+
+	if f.pretty {
+		cfg.Output.Pretty = true
+	}
+
+	if f.regions != "" {
+		cfg.Aws.Regions = strings.Split(f.regions, ",")
+		for name, ic := range cfg.Integrations {
+			ic.Regions = nil
+			cfg.Integrations[name] = ic
+		}
+	}
+
+	if f.baseline != "" {
+		cfg.Baseline = f.baseline
+	}
+
+	if f.history != "" {
+		cfg.History = f.history
+	}
+
+	if f.publish {
+		cfg.Datadog.Publish = true
+	}
+
+	if f.scanning && f.concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be positive")
+	}
+
+	return cfg, nil
+}
+
+// context returns Datadog authentication context with the timeout.
+func (f *commonFlags) context() (context.Context, context.CancelFunc) {
+	ctx := datadog.GetDatadogContext()
+	if f.timeout > 0 {
+		return context.WithTimeout(ctx, f.timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// parseIntegrations parses comma-separated integrations, and returns nil when it is empty.
+func parseIntegrations(s string) ([]datadog.IntegrationTarget, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	its := make([]datadog.IntegrationTarget, 0)
+	for _, name := range strings.Split(s, ",") {
+		it := datadog.IntegrationTarget(strings.TrimSpace(name))
+		if !datadog.IsSupportedIntegrationTarget(it) {
+			return nil, fmt.Errorf("unsupported integration %q", name)
+		}
+		its = append(its, it)
+	}
+
+	return its, nil
+}
+
+// getMonitorScopesMapping fetches the monitors matched with the configured query, and returns their scopes per metric.
+func getMonitorScopesMapping(ctx context.Context, cfg *config.Config, concurrency int) (datadog.MonitorScopesMapping, error) {
+	ddClient := datadog.GetDatadogClient()
+	metadata, err := datadog.GetMetadata(ctx, ddClient, cfg.Datadog.MonitorQuery)
+	if err != nil {
+		return nil, fmt.Errorf("faield to get monitor metadata: %w", err)
+	}
+
+	results, err := datadog.ListMonitors(ctx, ddClient, metadata, cfg.Datadog.MonitorQuery)
+	if err != nil {
+		return nil, fmt.Errorf("faield to list monitors: %w", err)
+	}
+
+	monitors, err := datadog.GetMonitors(ctx, ddClient, results, concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitors: %w", err)
+	}
+
+	ddMonitorScopesMapping, err := datadog.GetMonitorScopesMapping(monitors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitor/scopes mapping: %w", err)
+	}
+
+	return ddMonitorScopesMapping, nil
+}
+
+// runListIntegrations prints the supported integrations.
+func runListIntegrations(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("list-integrations", flag.ContinueOnError)
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	for _, it := range datadog.SupportedIntegrationTargets {
+		fmt.Fprintln(stdout, it)
+	}
+
+	return 0
+}
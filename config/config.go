@@ -0,0 +1,319 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v3"
+
+	"github.com/terakoya76/modd/datadog"
+)
+
+const (
+	// JSONFormat represents JSON output format.
+	JSONFormat = "json"
+	// CoverageFormat represents coverage matrix output format in JSON.
+	CoverageFormat = "coverage"
+	// TableFormat represents plain text table output format.
+	TableFormat = "table"
+	// MarkdownFormat represents Markdown output format.
+	MarkdownFormat = "markdown"
+	// JUnitFormat represents JUnit XML output format.
+	JUnitFormat = "junit"
+	// SARIFFormat represents SARIF output format.
+	SARIFFormat = "sarif"
+
+	// DefaultExitCodeUnmonitored represents the exit code when any threshold is breached.
+	DefaultExitCodeUnmonitored = 2
+	// DefaultExitCodePartialFailure represents the exit code when some metrics fail to be evaluated.
+	DefaultExitCodePartialFailure = 3
+	// DefaultExitCodeFatal represents the exit code when the scan cannot be completed.
+	DefaultExitCodeFatal = 1
+
+	// TagMatchAll represents that all the tag key pairs must be matched.
+	TagMatchAll = "all"
+	// TagMatchAny represents that any of the tag key pairs must be matched.
+	TagMatchAny = "any"
+)
+
+// DefaultMonitorQuery represents the monitor search query which covers metric-based monitors.
+// `metric` covers both metric alerts and query alerts.
+const DefaultMonitorQuery = "type:(metric OR integration OR anomaly OR forecast)"
+
+// DefaultAwsEc2InstanceStates represents EC2 instance states targeted by default.
+// Stopped/terminated instances are excluded since they never emit metrics.
+var DefaultAwsEc2InstanceStates = []string{"pending", "running"}
+
+// Config represents modd configuration.
+// Baseline is the path to the baseline file of the accepted unmonitored resources,
+// and History is the path to the database file recording every scan result.
+type Config struct {
+	Datadog      DatadogConfig                `yaml:"datadog"`
+	Aws          AwsConfig                    `yaml:"aws"`
+	Integrations map[string]IntegrationConfig `yaml:"integrations"`
+	Output       OutputConfig                 `yaml:"output"`
+	Exit         ExitConfig                   `yaml:"exit"`
+	Baseline     string                       `yaml:"baseline"`
+	History      string                       `yaml:"history"`
+}
+
+// DatadogConfig holds metadata to fetch Datadog monitors.
+// MonitorQuery is a monitor search query, e.g. `type:metric tag:team:sre`,
+// and Publish enables submitting the scan result back to Datadog as custom metrics and events.
+type DatadogConfig struct {
+	MonitorQuery string `yaml:"monitor_query"`
+	Publish      bool   `yaml:"publish"`
+}
+
+// AwsConfig holds metadata shared by every AWS integration.
+type AwsConfig struct {
+	Regions  []string `yaml:"regions"`
+	Accounts []string `yaml:"accounts"`
+	RoleName string   `yaml:"role_name"`
+}
+
+// IntegrationConfig holds metadata for the specific integration.
+// Regions/Accounts/RoleName fallback to the ones of AwsConfig when they are empty.
+type IntegrationConfig struct {
+	TagKeys        []TagKeyPair `yaml:"tag_keys"`
+	TagMatch       string       `yaml:"tag_match"`
+	Ignore         []IgnoreRule `yaml:"ignore"`
+	Regions        []string     `yaml:"regions"`
+	Accounts       []string     `yaml:"accounts"`
+	RoleName       string       `yaml:"role_name"`
+	InstanceStates []string     `yaml:"instance_states"`
+
+	RequiredMetrics []RequiredMetricsRule `yaml:"required_metrics"`
+}
+
+// TagKeyPair represents a pair of AWS tag key and Datadog tag key to be matched.
+// Values maps AWS tag values into Datadog tag values, e.g. `aurora-postgresql: postgres`.
+type TagKeyPair struct {
+	AwsTagKey string            `yaml:"aws"`
+	DdTagKey  string            `yaml:"datadog"`
+	Values    map[string]string `yaml:"values"`
+}
+
+// IgnoreRule represents a rule to ignore resources which should not be monitored.
+// Resource is a glob pattern of resource identifiers, Tag is a resource tag like `env:sandbox`.
+type IgnoreRule struct {
+	Resource string `yaml:"resource"`
+	Tag      string `yaml:"tag"`
+}
+
+// RequiredMetricsRule represents metrics which must be monitored for the resources matched with Scope.
+// Scope is a Datadog scope expression like `env:production`, and every resource is matched when it is empty.
+type RequiredMetricsRule struct {
+	Metrics []string `yaml:"metrics"`
+	Scope   string   `yaml:"scope"`
+}
+
+// OutputConfig holds metadata for output.
+type OutputConfig struct {
+	Format string `yaml:"format"`
+	Pretty bool   `yaml:"pretty"`
+}
+
+// ExitConfig holds metadata to decide the exit code of a scan.
+type ExitConfig struct {
+	Codes      ExitCodes  `yaml:"codes"`
+	Thresholds Thresholds `yaml:"thresholds"`
+}
+
+// ExitCodes represents the exit codes of a scan.
+// Unmonitored is used when any threshold is breached, PartialFailure when some metrics fail to be evaluated,
+// and Fatal when the scan cannot be completed.
+type ExitCodes struct {
+	Unmonitored    int `yaml:"unmonitored"`
+	PartialFailure int `yaml:"partial_failure"`
+	Fatal          int `yaml:"fatal"`
+}
+
+// Thresholds represents the limits of unmonitored resources.
+// MaxUnmonitored is the maximum number of unmonitored pairs of metric and resource,
+// and MinCoverage is the minimum coverage percentage per integration.
+type Thresholds struct {
+	MaxUnmonitored int                `yaml:"max_unmonitored"`
+	MinCoverage    map[string]float64 `yaml:"min_coverage"`
+}
+
+// legacyAwsConfig holds AWS metadata read from environment variables.
+type legacyAwsConfig struct {
+	Regions  []string `envconfig:"regions"`
+	Accounts []string `envconfig:"accounts"`
+	RoleName string   `envconfig:"role_name"`
+}
+
+// legacyIntegrationConfig holds integration metadata read from environment variables.
+// cf. AWS_RDS_AWS_TAG_KEY, AWS_RDS_DATADOG_TAG_KEY.
+type legacyIntegrationConfig struct {
+	AwsTagKey      string   `envconfig:"aws_tag_key"`
+	DdTagKey       string   `envconfig:"datadog_tag_key"`
+	InstanceStates []string `envconfig:"instance_states"`
+}
+
+// NewConfig returns the default Config.
+func NewConfig() *Config {
+	return &Config{
+		Datadog: DatadogConfig{
+			MonitorQuery: DefaultMonitorQuery,
+		},
+		Aws:          AwsConfig{},
+		Integrations: make(map[string]IntegrationConfig),
+		Output: OutputConfig{
+			Format: JSONFormat,
+			Pretty: false,
+		},
+		Exit: ExitConfig{
+			Codes: ExitCodes{
+				Unmonitored:    DefaultExitCodeUnmonitored,
+				PartialFailure: DefaultExitCodePartialFailure,
+				Fatal:          DefaultExitCodeFatal,
+			},
+		},
+	}
+}
+
+// Load reads the configuration file and overrides it with environment variables.
+// When path is empty, only environment variables are read.
+// The overridden configuration is validated, and an invalid value is reported with the environment variable name.
+func Load(path string) (*Config, error) {
+	cfg := NewConfig()
+
+	var root *yaml.Node
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		root, err = cfg.decode(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	env, err := cfg.overrideWithEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment variables: %w", err)
+	}
+
+	if err := cfg.validate(root, env); err != nil {
+		var envErr EnvError
+		if errors.As(err, &envErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Parse parses the configuration from YAML bytes.
+func Parse(b []byte) (*Config, error) {
+	cfg := NewConfig()
+	root, err := cfg.decode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(root, nil); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// decode decodes YAML bytes into Config and returns the root node to point out the position of an invalid value.
+// nil is returned for an empty document.
+func (c *Config) decode(b []byte) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(b, &root); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	// empty document
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return root.Content[0], nil
+}
+
+// overrideWithEnv overrides Config with environment variables,
+// and returns the dot-separated keys of the overridden values mapped into the environment variable names.
+func (c *Config) overrideWithEnv() (map[string]string, error) {
+	env := make(map[string]string)
+
+	var lac legacyAwsConfig
+	if err := envconfig.Process("aws", &lac); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if len(lac.Regions) > 0 {
+		c.Aws.Regions = lac.Regions
+		env["aws.regions"] = "AWS_REGIONS"
+	}
+	if len(lac.Accounts) > 0 {
+		c.Aws.Accounts = lac.Accounts
+		env["aws.accounts"] = "AWS_ACCOUNTS"
+	}
+	if lac.RoleName != "" {
+		c.Aws.RoleName = lac.RoleName
+		env["aws.role_name"] = "AWS_ROLE_NAME"
+	}
+
+	for _, it := range datadog.SupportedIntegrationTargets {
+		var lic legacyIntegrationConfig
+		if err := envconfig.Process(string(it), &lic); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		prefix := strings.ToUpper(string(it))
+		ic := c.Integrations[string(it)]
+		if lic.AwsTagKey != "" || lic.DdTagKey != "" {
+			ic.TagKeys = []TagKeyPair{{AwsTagKey: lic.AwsTagKey, DdTagKey: lic.DdTagKey}}
+			env[fmt.Sprintf("integrations.%s.tag_keys", it)] = fmt.Sprintf("%s_AWS_TAG_KEY, %s_DATADOG_TAG_KEY", prefix, prefix)
+		}
+		if lic.InstanceStates != nil {
+			ic.InstanceStates = lic.InstanceStates
+			env[fmt.Sprintf("integrations.%s.instance_states", it)] = fmt.Sprintf("%s_INSTANCE_STATES", prefix)
+		}
+		c.Integrations[string(it)] = ic
+	}
+
+	return env, nil
+}
+
+// Integration returns IntegrationConfig for the specified IntegrationTarget filled with the shared/default values.
+func (c *Config) Integration(it datadog.IntegrationTarget) IntegrationConfig {
+	ic := c.Integrations[string(it)]
+
+	if len(ic.Regions) == 0 {
+		ic.Regions = c.Aws.Regions
+	}
+	if len(ic.Accounts) == 0 {
+		ic.Accounts = c.Aws.Accounts
+	}
+	if ic.RoleName == "" {
+		ic.RoleName = c.Aws.RoleName
+	}
+	if ic.TagMatch == "" {
+		ic.TagMatch = TagMatchAll
+	}
+	if it == datadog.AwsEc2 && ic.InstanceStates == nil {
+		ic.InstanceStates = DefaultAwsEc2InstanceStates
+	}
+
+	return ic
+}
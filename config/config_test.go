@@ -0,0 +1,412 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
+)
+
+func Test_Parse(t *testing.T) {
+	cases := []struct {
+		name     string
+		yaml     string
+		expected *config.Config
+		err      string
+	}{
+		{
+			name: "when empty document",
+			yaml: "",
+			expected: &config.Config{
+				Datadog:      config.DatadogConfig{MonitorQuery: config.DefaultMonitorQuery},
+				Aws:          config.AwsConfig{},
+				Integrations: map[string]config.IntegrationConfig{},
+				Output:       config.OutputConfig{Format: "json"},
+				Exit:         config.ExitConfig{Codes: config.ExitCodes{Unmonitored: 2, PartialFailure: 3, Fatal: 1}},
+			},
+			err: "",
+		},
+		{
+			name: "when valid document",
+			yaml: `
+datadog:
+  monitor_query: "type:metric tag:team:sre"
+aws:
+  regions: [us-east-1, ap-northeast-1]
+  accounts: ["123456789012"]
+  role_name: modd
+integrations:
+  aws_rds:
+    tag_keys:
+      - aws: engine
+        datadog: dbengine
+        values:
+          aurora-postgresql: postgres
+      - aws: env
+        datadog: env
+    tag_match: any
+    ignore:
+      - resource: sandbox-*
+      - tag: env:sandbox
+  aws_ec2:
+    regions: [us-west-2]
+    instance_states: [running, stopped]
+output:
+  pretty: true
+exit:
+  codes:
+    unmonitored: 10
+  thresholds:
+    max_unmonitored: 5
+    min_coverage:
+      aws_rds: 90
+`,
+			expected: &config.Config{
+				Datadog: config.DatadogConfig{MonitorQuery: "type:metric tag:team:sre"},
+				Aws: config.AwsConfig{
+					Regions:  []string{"us-east-1", "ap-northeast-1"},
+					Accounts: []string{"123456789012"},
+					RoleName: "modd",
+				},
+				Integrations: map[string]config.IntegrationConfig{
+					"aws_rds": {
+						TagKeys: []config.TagKeyPair{
+							{AwsTagKey: "engine", DdTagKey: "dbengine", Values: map[string]string{"aurora-postgresql": "postgres"}},
+							{AwsTagKey: "env", DdTagKey: "env"},
+						},
+						TagMatch: "any",
+						Ignore: []config.IgnoreRule{
+							{Resource: "sandbox-*"},
+							{Tag: "env:sandbox"},
+						},
+					},
+					"aws_ec2": {
+						Regions:        []string{"us-west-2"},
+						InstanceStates: []string{"running", "stopped"},
+					},
+				},
+				Output: config.OutputConfig{Format: "json", Pretty: true},
+				Exit: config.ExitConfig{
+					Codes: config.ExitCodes{Unmonitored: 10, PartialFailure: 3, Fatal: 1},
+					Thresholds: config.Thresholds{
+						MaxUnmonitored: 5,
+						MinCoverage:    map[string]float64{"aws_rds": 90},
+					},
+				},
+			},
+			err: "",
+		},
+		{
+			name: "when unknown field",
+			yaml: `
+integrations:
+  aws_rds:
+    tag_key: engine
+`,
+			expected: nil,
+			err:      "yaml: unmarshal errors:\n  line 4: field tag_key not found in type config.IntegrationConfig",
+		},
+		{
+			name: "when unsupported integration",
+			yaml: `
+integrations:
+  aws_rds: {}
+  aws_foo: {}
+`,
+			expected: nil,
+			err:      `line 4, column 3: unsupported integration "aws_foo"`,
+		},
+		{
+			name: "when tag key pair lacks datadog tag key",
+			yaml: `
+integrations:
+  aws_rds:
+    tag_keys:
+      - aws: engine
+        datadog: dbengine
+      - aws: env
+`,
+			expected: nil,
+			err:      "line 7, column 9: both aws and datadog tag keys are required",
+		},
+		{
+			name: "when unknown tag_match",
+			yaml: `
+integrations:
+  aws_rds:
+    tag_match: some
+`,
+			expected: nil,
+			err:      `line 4, column 16: unknown tag_match "some", must be one of all, any`,
+		},
+		{
+			name: "when ignore rule has invalid pattern",
+			yaml: `
+integrations:
+  aws_sqs:
+    ignore:
+      - resource: "[a-"
+`,
+			expected: nil,
+			err:      `line 5, column 19: invalid resource pattern "[a-": syntax error in pattern`,
+		},
+		{
+			name: "when account ID is specified without role name",
+			yaml: `
+aws:
+  accounts:
+    - "123456789012"
+`,
+			expected: nil,
+			err:      "line 4, column 7: role_name is required to assume role in the account 123456789012",
+		},
+		{
+			name: "when instance states are specified for non EC2 integration",
+			yaml: `
+integrations:
+  aws_rds:
+    instance_states: [running]
+`,
+			expected: nil,
+			err:      "line 4, column 5: instance_states is only available for aws_ec2",
+		},
+		{
+			name: "when required metric belongs to another integration",
+			yaml: `
+integrations:
+  aws_rds:
+    required_metrics:
+      - metrics: [aws.rds.cpuutilization, aws.ec2.cpuutilization]
+        scope: env:production
+`,
+			expected: nil,
+			err:      `line 5, column 43: metric "aws.ec2.cpuutilization" does not belong to aws_rds`,
+		},
+		{
+			name: "when required metrics scope is invalid",
+			yaml: `
+integrations:
+  aws_rds:
+    required_metrics:
+      - metrics: [aws.rds.cpuutilization]
+        scope: "env IN (prod"
+`,
+			expected: nil,
+			err:      `line 6, column 16: invalid scope "env IN (prod": missing closing parenthesis of env IN`,
+		},
+		{
+			name: "when monitor query is empty",
+			yaml: `
+datadog:
+  monitor_query: ""
+`,
+			expected: nil,
+			err:      "line 3, column 18: monitor_query must not be empty",
+		},
+		{
+			name: "when exit code is out of range",
+			yaml: `
+exit:
+  codes:
+    fatal: 256
+`,
+			expected: nil,
+			err:      "line 4, column 12: exit code 256 must be between 0 and 255",
+		},
+		{
+			name: "when min coverage is out of range",
+			yaml: `
+exit:
+  thresholds:
+    min_coverage:
+      aws_rds: 120
+`,
+			expected: nil,
+			err:      "line 5, column 16: min_coverage 120 must be between 0 and 100",
+		},
+		{
+			name: "when unsupported output format",
+			yaml: `
+output:
+  format: xml
+`,
+			expected: nil,
+			err:      `line 3, column 11: unsupported output format "xml", must be one of json, coverage, table, markdown, junit, sarif`,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := config.Parse([]byte(c.yaml))
+		if c.err != "" {
+			if !assert.EqualError(t, err, c.err) {
+				t.Errorf("case: %s is failed, expected: %s, actual: %+v\n", c.name, c.err, err)
+			}
+			continue
+		}
+
+		if !assert.Nil(t, err) {
+			t.Errorf("case: %s is failed, err: %+v\n", c.name, err)
+		}
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_Load_WithEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "modd.yaml")
+	content := `
+aws:
+  regions: [us-east-1]
+integrations:
+  aws_rds:
+    tag_keys:
+      - aws: engine
+        datadog: dbengine
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("AWS_REGIONS", "ap-northeast-1,us-west-2")
+	t.Setenv("AWS_RDS_AWS_TAG_KEY", "service")
+	t.Setenv("AWS_RDS_DATADOG_TAG_KEY", "service")
+
+	cfg, err := config.Load(path)
+	if !assert.Nil(t, err) {
+		t.Fatalf("failed to load config: %+v\n", err)
+	}
+
+	assert.Equal(t, []string{"ap-northeast-1", "us-west-2"}, cfg.Aws.Regions)
+	assert.Equal(t, []config.TagKeyPair{{AwsTagKey: "service", DdTagKey: "service"}}, cfg.Integration(datadog.AwsRds).TagKeys)
+}
+
+func Test_Load_WithInvalidEnv(t *testing.T) {
+	cases := []struct {
+		name string
+		yaml string
+		env  map[string]string
+		err  string
+	}{
+		{
+			name: "when account from env lacks role name",
+			yaml: `
+aws:
+  regions: [us-east-1]
+`,
+			env: map[string]string{"AWS_ACCOUNTS": "123456789012"},
+			err: "AWS_ACCOUNTS: role_name is required to assume role in the account 123456789012",
+		},
+		{
+			name: "when instance states from env are specified for non EC2 integration",
+			yaml: "",
+			env:  map[string]string{"AWS_RDS_INSTANCE_STATES": "running"},
+			err:  "AWS_RDS_INSTANCE_STATES: instance_states is only available for aws_ec2",
+		},
+		{
+			name: "when tag key pair from env lacks datadog tag key",
+			yaml: `
+integrations:
+  aws_rds:
+    tag_keys:
+      - aws: engine
+        datadog: dbengine
+`,
+			env: map[string]string{"AWS_RDS_AWS_TAG_KEY": "service"},
+			err: "AWS_RDS_AWS_TAG_KEY, AWS_RDS_DATADOG_TAG_KEY: both aws and datadog tag keys are required",
+		},
+		{
+			name: "when file is invalid regardless of env",
+			yaml: `
+integrations:
+  aws_rds:
+    tag_match: some
+`,
+			env: map[string]string{"AWS_REGIONS": "us-east-1"},
+			err: `line 4, column 16: unknown tag_match "some", must be one of all, any`,
+		},
+	}
+
+	for _, c := range cases {
+		path := filepath.Join(t.TempDir(), "modd.yaml")
+		if err := os.WriteFile(path, []byte(c.yaml), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		for k, v := range c.env {
+			t.Setenv(k, v)
+		}
+
+		_, err := config.Load(path)
+		if !assert.ErrorContains(t, err, c.err) {
+			t.Errorf("case: %s is failed, expected: %s, actual: %+v\n", c.name, c.err, err)
+		}
+
+		for k := range c.env {
+			if err := os.Unsetenv(k); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func Test_Integration(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Aws = config.AwsConfig{
+		Regions:  []string{"us-east-1"},
+		Accounts: []string{"123456789012"},
+		RoleName: "modd",
+	}
+	cfg.Integrations["aws_sqs"] = config.IntegrationConfig{
+		Regions: []string{"ap-northeast-1"},
+	}
+
+	cases := []struct {
+		name     string
+		it       datadog.IntegrationTarget
+		expected config.IntegrationConfig
+	}{
+		{
+			name: "when integration overrides regions",
+			it:   datadog.AwsSqs,
+			expected: config.IntegrationConfig{
+				Regions:  []string{"ap-northeast-1"},
+				Accounts: []string{"123456789012"},
+				RoleName: "modd",
+				TagMatch: "all",
+			},
+		},
+		{
+			name: "when integration is not configured",
+			it:   datadog.AwsRds,
+			expected: config.IntegrationConfig{
+				Regions:  []string{"us-east-1"},
+				Accounts: []string{"123456789012"},
+				RoleName: "modd",
+				TagMatch: "all",
+			},
+		},
+		{
+			name: "when EC2 integration fills default instance states",
+			it:   datadog.AwsEc2,
+			expected: config.IntegrationConfig{
+				Regions:        []string{"us-east-1"},
+				Accounts:       []string{"123456789012"},
+				RoleName:       "modd",
+				TagMatch:       "all",
+				InstanceStates: config.DefaultAwsEc2InstanceStates,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := cfg.Integration(c.it)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
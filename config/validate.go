@@ -0,0 +1,294 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/terakoya76/modd/datadog"
+)
+
+var (
+	accountPattern = regexp.MustCompile(`^([0-9]{12}|arn:[^:]+:iam::[0-9]{12}:role/.+)$`)
+
+	ec2InstanceStates = []string{"pending", "running", "shutting-down", "terminated", "stopping", "stopped"}
+
+	outputFormats = []string{JSONFormat, CoverageFormat, TableFormat, MarkdownFormat, JUnitFormat, SARIFFormat}
+
+	tagMatches = []string{TagMatchAll, TagMatchAny}
+)
+
+// ValidationError represents an invalid configuration with the position in the file.
+type ValidationError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error implements error interface.
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// EnvError represents an invalid value read from the environment variable.
+type EnvError struct {
+	Name    string
+	Message string
+}
+
+// Error implements error interface.
+func (e EnvError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Message)
+}
+
+func newValidationError(node *yaml.Node, format string, args ...interface{}) ValidationError {
+	return ValidationError{
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// locator points out where an invalid value comes from,
+// the position in the configuration file or the environment variable overriding it.
+// env maps the dot-separated keys of the overridden values into the environment variable names.
+type locator struct {
+	root *yaml.Node
+	env  map[string]string
+}
+
+// errorf returns the error at the value node found along with the keys.
+func (l locator) errorf(keys []string, format string, args ...interface{}) error {
+	return l.newError(false, keys, format, args...)
+}
+
+// keyErrorf is the same as errorf except that it points out the key node.
+func (l locator) keyErrorf(keys []string, format string, args ...interface{}) error {
+	return l.newError(true, keys, format, args...)
+}
+
+func (l locator) newError(keyNode bool, keys []string, format string, args ...interface{}) error {
+	for i := len(keys); i > 0; i-- {
+		if name, ok := l.env[strings.Join(keys[:i], ".")]; ok {
+			return EnvError{Name: name, Message: fmt.Sprintf(format, args...)}
+		}
+	}
+
+	return newValidationError(find(l.root, keyNode, keys...), format, args...)
+}
+
+// validate checks the semantics of Config which cannot be checked on decoding.
+// root is used to point out the position of an invalid value, and could be nil when no configuration file is read.
+func (c *Config) validate(root *yaml.Node, env map[string]string) error {
+	if root == nil {
+		root = &yaml.Node{Kind: yaml.MappingNode}
+	}
+	l := locator{root: root, env: env}
+
+	if strings.TrimSpace(c.Datadog.MonitorQuery) == "" {
+		return l.errorf(keys("datadog", "monitor_query"), "monitor_query must not be empty")
+	}
+
+	if err := validateAccounts(c.Aws.Accounts, c.Aws.RoleName, l, "aws"); err != nil {
+		return err
+	}
+
+	for name, ic := range c.Integrations {
+		it := datadog.IntegrationTarget(name)
+		if !datadog.IsSupportedIntegrationTarget(it) {
+			return l.keyErrorf(keys("integrations", name), "unsupported integration %q", name)
+		}
+
+		if err := validateIntegration(it, ic, c.Aws.RoleName, l); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateOutputFormat(c.Output.Format); err != nil {
+		return l.errorf(keys("output", "format"), "%v", err)
+	}
+
+	return validateExit(c.Exit, l)
+}
+
+func keys(k ...string) []string {
+	return k
+}
+
+func validateExit(ec ExitConfig, l locator) error {
+	codes := map[string]int{
+		"unmonitored":     ec.Codes.Unmonitored,
+		"partial_failure": ec.Codes.PartialFailure,
+		"fatal":           ec.Codes.Fatal,
+	}
+	for key, code := range codes {
+		if code < 0 || code > 255 {
+			return l.errorf(keys("exit", "codes", key), "exit code %d must be between 0 and 255", code)
+		}
+	}
+
+	if ec.Thresholds.MaxUnmonitored < 0 {
+		return l.errorf(keys("exit", "thresholds", "max_unmonitored"), "max_unmonitored must not be negative")
+	}
+
+	for name, coverage := range ec.Thresholds.MinCoverage {
+		if !datadog.IsSupportedIntegrationTarget(datadog.IntegrationTarget(name)) {
+			return l.keyErrorf(keys("exit", "thresholds", "min_coverage", name), "unsupported integration %q", name)
+		}
+
+		if coverage < 0 || coverage > 100 {
+			return l.errorf(keys("exit", "thresholds", "min_coverage", name), "min_coverage %g must be between 0 and 100", coverage)
+		}
+	}
+
+	return nil
+}
+
+// ValidateOutputFormat checks whether the output format is supported.
+func ValidateOutputFormat(format string) error {
+	if !contains(outputFormats, format) {
+		return fmt.Errorf("unsupported output format %q, must be one of %s", format, strings.Join(outputFormats, ", "))
+	}
+
+	return nil
+}
+
+func validateIntegration(it datadog.IntegrationTarget, ic IntegrationConfig, roleName string, l locator) error {
+	name := string(it)
+
+	for i, pair := range ic.TagKeys {
+		if pair.AwsTagKey == "" || pair.DdTagKey == "" {
+			return l.errorf(keys("integrations", name, "tag_keys", strconv.Itoa(i)), "both aws and datadog tag keys are required")
+		}
+	}
+
+	if ic.TagMatch != "" && !contains(tagMatches, ic.TagMatch) {
+		return l.errorf(keys("integrations", name, "tag_match"),
+			"unknown tag_match %q, must be one of %s", ic.TagMatch, strings.Join(tagMatches, ", "))
+	}
+
+	for i, rule := range ic.Ignore {
+		k := keys("integrations", name, "ignore", strconv.Itoa(i))
+		if (rule.Resource == "") == (rule.Tag == "") {
+			return l.errorf(k, "either resource or tag is required")
+		}
+
+		if _, err := path.Match(rule.Resource, ""); err != nil {
+			return l.errorf(append(k, "resource"), "invalid resource pattern %q: %v", rule.Resource, err)
+		}
+
+		if rule.Tag != "" && !strings.Contains(rule.Tag, ":") {
+			return l.errorf(append(k, "tag"), "invalid tag %q, must be formatted as key:value", rule.Tag)
+		}
+	}
+
+	for i, rule := range ic.RequiredMetrics {
+		k := keys("integrations", name, "required_metrics", strconv.Itoa(i))
+		if len(rule.Metrics) == 0 {
+			return l.errorf(k, "metrics are required")
+		}
+
+		for j, metric := range rule.Metrics {
+			if datadog.MetricToIntegrationTarget(metric) != it {
+				return l.errorf(append(k, "metrics", strconv.Itoa(j)), "metric %q does not belong to %s", metric, name)
+			}
+		}
+
+		if rule.Scope != "" {
+			if _, err := datadog.ParseScope(rule.Scope); err != nil {
+				return l.errorf(append(k, "scope"), "invalid scope %q: %v", rule.Scope, err)
+			}
+		}
+	}
+
+	if ic.RoleName != "" {
+		roleName = ic.RoleName
+	}
+	if err := validateAccounts(ic.Accounts, roleName, l, "integrations", name); err != nil {
+		return err
+	}
+
+	if len(ic.InstanceStates) > 0 && it != datadog.AwsEc2 {
+		return l.keyErrorf(keys("integrations", name, "instance_states"), "instance_states is only available for %s", datadog.AwsEc2)
+	}
+
+	for i, state := range ic.InstanceStates {
+		if !contains(ec2InstanceStates, state) {
+			return l.errorf(keys("integrations", name, "instance_states", strconv.Itoa(i)),
+				"unknown instance state %q, must be one of %s", state, strings.Join(ec2InstanceStates, ", "))
+		}
+	}
+
+	return nil
+}
+
+func validateAccounts(accounts []string, roleName string, l locator, parents ...string) error {
+	for i, account := range accounts {
+		k := append(append([]string{}, parents...), "accounts", strconv.Itoa(i))
+		if !accountPattern.MatchString(account) {
+			return l.errorf(k, "invalid account %q, must be an account ID or a role ARN", account)
+		}
+
+		if !strings.HasPrefix(account, "arn:") && roleName == "" {
+			return l.errorf(k, "role_name is required to assume role in the account %s", account)
+		}
+	}
+
+	return nil
+}
+
+// find returns the deepest node found along with the keys.
+// A sequence item is specified by its index, and the key node is returned instead of the value node when keyNode is true.
+func find(node *yaml.Node, keyNode bool, keys ...string) *yaml.Node {
+	current := node
+	for i, key := range keys {
+		last := i == len(keys)-1
+
+		switch current.Kind {
+		case yaml.MappingNode:
+			found := false
+			for j := 0; j+1 < len(current.Content); j += 2 {
+				if current.Content[j].Value != key {
+					continue
+				}
+
+				found = true
+				if last && keyNode {
+					return current.Content[j]
+				}
+				current = current.Content[j+1]
+				break
+			}
+
+			if !found {
+				return current
+			}
+
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx >= len(current.Content) {
+				return current
+			}
+			current = current.Content[idx]
+
+		case yaml.DocumentNode, yaml.ScalarNode, yaml.AliasNode:
+			return current
+		}
+	}
+
+	return current
+}
+
+func contains(arr []string, s string) bool {
+	for _, elmt := range arr {
+		if elmt == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -19,6 +19,8 @@ const (
 	AwsClb IntegrationTarget = "aws_elb"
 	// AwsDynamoDB represents AWS DynamoDB integration.
 	AwsDynamoDB IntegrationTarget = "aws_dynamodb"
+	// AwsEc2 represents AWS EC2 integration.
+	AwsEc2 IntegrationTarget = "aws_ec2"
 	// AwsElastiCache represents AWS ElastiCache integration.
 	AwsElastiCache IntegrationTarget = "aws_elasticache"
 	// AwsElb represents AWS ALB/NLB integration.
@@ -27,6 +29,8 @@ const (
 	AwsFirehose IntegrationTarget = "aws_firehose"
 	// AwsKinesis represents AWS Kinesis integration.
 	AwsKinesis IntegrationTarget = "aws_kinesis"
+	// AwsLambda represents AWS Lambda integration.
+	AwsLambda IntegrationTarget = "aws_lambda"
 	// AwsOpenSearchService represents AWS OpenSearch Service integration.
 	AwsOpenSearchService IntegrationTarget = "aws_elasticsearchservice"
 	// AwsRds represents AWS RDS integration.
@@ -42,6 +46,36 @@ const (
 	UnknownIntegration IntegrationTarget = "unknown"
 )
 
+// SupportedIntegrationTargets represents a list of IntegrationTarget which modd supports.
+var SupportedIntegrationTargets = []IntegrationTarget{
+	AwsAPIGateway,
+	AwsAutoScalingGroup,
+	AwsClb,
+	AwsDynamoDB,
+	AwsEc2,
+	AwsElastiCache,
+	AwsElb,
+	AwsFirehose,
+	AwsKinesis,
+	AwsLambda,
+	AwsOpenSearchService,
+	AwsRds,
+	AwsSns,
+	AwsStepFunction,
+	AwsSqs,
+}
+
+// IsSupportedIntegrationTarget returns whether the specified IntegrationTarget is supported or not.
+func IsSupportedIntegrationTarget(it IntegrationTarget) bool {
+	for _, supported := range SupportedIntegrationTargets {
+		if it == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
 // MetricToIntegrationTarget returns the IntegrationTarget to which the specified metric belongs.
 //
 //nolint:gocyclo
@@ -56,6 +90,8 @@ func MetricToIntegrationTarget(metric string) IntegrationTarget {
 		return AwsClb
 	case len(parts) >= 2 && parts[0] == AwsMetricsPrefix && parts[1] == "dynamodb":
 		return AwsDynamoDB
+	case len(parts) >= 2 && parts[0] == AwsMetricsPrefix && parts[1] == "ec2":
+		return AwsEc2
 	case len(parts) >= 2 && parts[0] == AwsMetricsPrefix && parts[1] == "elasticache":
 		return AwsElastiCache
 	case len(parts) >= 2 && parts[0] == AwsMetricsPrefix && parts[1] == "applicationelb":
@@ -64,6 +100,8 @@ func MetricToIntegrationTarget(metric string) IntegrationTarget {
 		return AwsFirehose
 	case len(parts) >= 2 && parts[0] == AwsMetricsPrefix && parts[1] == "kinesis":
 		return AwsKinesis
+	case len(parts) >= 2 && parts[0] == AwsMetricsPrefix && parts[1] == "lambda":
+		return AwsLambda
 	case len(parts) >= 2 && parts[0] == AwsMetricsPrefix && parts[1] == "es":
 		return AwsOpenSearchService
 	case len(parts) >= 2 && parts[0] == AwsMetricsPrefix && parts[1] == "rds":
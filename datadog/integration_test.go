@@ -34,6 +34,11 @@ func Test_MetricToIntegrationTarget(t *testing.T) {
 			metric:   "aws.dynamodb.item_count",
 			expected: datadog.AwsDynamoDB,
 		},
+		{
+			name:     "when AWS EC2",
+			metric:   "aws.ec2.cpuutilization",
+			expected: datadog.AwsEc2,
+		},
 		{
 			name:     "when AWS ElastiCache",
 			metric:   "aws.elasticache.cache_hits",
@@ -54,6 +59,11 @@ func Test_MetricToIntegrationTarget(t *testing.T) {
 			metric:   "aws.kinesis.get_records_latency",
 			expected: datadog.AwsKinesis,
 		},
+		{
+			name:     "when AWS Lambda",
+			metric:   "aws.lambda.errors",
+			expected: datadog.AwsLambda,
+		},
 		{
 			name:     "when AWS OpenSearchService",
 			metric:   "aws.es.elasticsearch_requests",
@@ -88,3 +98,34 @@ func Test_MetricToIntegrationTarget(t *testing.T) {
 		}
 	}
 }
+
+func Test_IsSupportedIntegrationTarget(t *testing.T) {
+	cases := []struct {
+		name     string
+		it       datadog.IntegrationTarget
+		expected bool
+	}{
+		{
+			name:     "when supported integration",
+			it:       datadog.AwsRds,
+			expected: true,
+		},
+		{
+			name:     "when unknown integration",
+			it:       datadog.UnknownIntegration,
+			expected: false,
+		},
+		{
+			name:     "when undefined integration",
+			it:       datadog.IntegrationTarget("aws_foo"),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		actual := datadog.IsSupportedIntegrationTarget(c.it)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %v, actual: %v\n", c.name, c.expected, actual)
+		}
+	}
+}
@@ -8,20 +8,37 @@ import (
 	"strings"
 
 	dd "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"golang.org/x/sync/errgroup"
 )
 
 // Scope represents Datadog monitor scope.
 // cf. []string{"stage:production", "service:user"}.
 type Scope = []string
 
-// MonitorScopesMapping represents a mapping of Datadog monitor ID and its scope.
-type MonitorScopesMapping = map[string][]Scope
+// MonitorRef identifies a Datadog monitor.
+type MonitorRef struct {
+	ID   int64
+	Name string
+}
+
+// MonitorScope represents a scope of the metric query attributed to the Datadog monitor.
+// GroupBy holds the group by keys of the metric query,
+// and Tags are the tags of the monitor to be matched with the resource tags.
+type MonitorScope struct {
+	Monitor MonitorRef
+	Scope   Scope
+	GroupBy []string
+	Tags    Tags
+}
+
+// MonitorScopesMapping represents a mapping of metric and the monitor scopes querying it.
+type MonitorScopesMapping = map[string][]MonitorScope
 
 // Tags represents Datadog tags.
 type Tags = []string
 
-// MonitorTagsMapping represents a mapping of Datadog monitor ID and its tags.
-type MonitorTagsMapping = map[string]Tags
+// scopeTermSeparator joins scope terms into a key since a term could contain ',', e.g. `env IN (prod,stg)`.
+const scopeTermSeparator = "\x00"
 
 // GetDatadogContext returns Datadog authentication context.
 func GetDatadogContext() context.Context {
@@ -45,9 +62,13 @@ func GetDatadogClient() *dd.APIClient {
 	return dd.NewAPIClient(configuration)
 }
 
-// GetMetadata returns Datadog SearchMonitors Metadata.
-func GetMetadata(ctx context.Context, ddClient *dd.APIClient) (*dd.MonitorSearchResponseMetadata, error) {
-	resp, _, err := ddClient.MonitorsApi.SearchMonitors(ctx)
+// GetMetadata returns Datadog SearchMonitors Metadata for the query.
+func GetMetadata(ctx context.Context, ddClient *dd.APIClient, query string) (*dd.MonitorSearchResponseMetadata, error) {
+	optionalParams := dd.SearchMonitorsOptionalParameters{
+		Query: &query,
+	}
+
+	resp, _, err := ddClient.MonitorsApi.SearchMonitors(ctx, optionalParams)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -56,13 +77,13 @@ func GetMetadata(ctx context.Context, ddClient *dd.APIClient) (*dd.MonitorSearch
 	return &m, nil
 }
 
-// ListMonitors returns a list of Datadog monitors.
+// ListMonitors returns a list of Datadog monitors matched with the query.
+// cf. https://docs.datadoghq.com/monitors/manage/search/
 func ListMonitors(
-	ctx context.Context, ddClient *dd.APIClient, metadata *dd.MonitorSearchResponseMetadata,
+	ctx context.Context, ddClient *dd.APIClient, metadata *dd.MonitorSearchResponseMetadata, query string,
 ) ([]dd.MonitorSearchResult, error) {
 	monitors := make([]dd.MonitorSearchResult, 0, metadata.GetTotalCount())
 
-	query := "type:integration"
 	sortKey := "name,asc"
 	perPage := int64(100)
 	pages := int(metadata.GetTotalCount()/perPage + 1)
@@ -87,61 +108,73 @@ func ListMonitors(
 	return monitors, nil
 }
 
-// GetMonitorTagsMapping returns the latest MonitorTagsMapping.
-func GetMonitorTagsMapping(monitors []dd.MonitorSearchResult) (MonitorTagsMapping, error) {
-	mapping := make(MonitorTagsMapping)
+// GetMonitors returns the full definitions of the searched monitors fetching at most concurrency monitors at once.
+// A search result lacks the monitor query, which is necessary to extract metrics and scopes precisely.
+func GetMonitors(ctx context.Context, ddClient *dd.APIClient, results []dd.MonitorSearchResult, concurrency int) ([]dd.Monitor, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	monitors := make([]dd.Monitor, len(results))
+	sem := make(chan struct{}, concurrency)
 
-	for i := 0; i < len(monitors); i++ {
-		monitor := monitors[i]
-		tags := monitor.GetTags()
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, result := range results {
+		idx, id := i, result.GetId()
+		eg.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-		for _, metric := range monitor.GetMetrics() {
-			if _, ok := mapping[metric]; ok {
-				mapping[metric] = append(mapping[metric], tags...)
-			} else {
-				mapping[metric] = tags
+			monitor, _, err := ddClient.MonitorsApi.GetMonitor(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get monitor %d: %w", id, err)
 			}
-		}
+
+			monitors[idx] = monitor
+			return nil
+		})
 	}
 
-	return mapping, nil
+	if err := eg.Wait(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return monitors, nil
 }
 
 // GetMonitorScopesMapping returns the latest MonitorScopesMapping.
-func GetMonitorScopesMapping(monitors []dd.MonitorSearchResult) (MonitorScopesMapping, error) {
+// Scopes are extracted from each metric query of the monitor query,
+// and the same scope grouped by the same keys queried by a monitor more than once is deduplicated.
+func GetMonitorScopesMapping(monitors []dd.Monitor) (MonitorScopesMapping, error) {
 	mapping := make(MonitorScopesMapping)
+	seen := make(map[string]struct{})
 
 	for i := 0; i < len(monitors); i++ {
 		monitor := monitors[i]
-		scopes := monitor.GetScopes()
-
-		for _, metric := range monitor.GetMetrics() {
-			mapping[metric] = append(mapping[metric], scopes)
+		ref := MonitorRef{
+			ID:   monitor.GetId(),
+			Name: monitor.GetName(),
 		}
-	}
 
-	for metric, scopes := range mapping {
-		mapping[metric] = MakeUniq(scopes)
-	}
+		for _, q := range ParseMonitorQuery(monitor.GetQuery()) {
+			scope := make(Scope, len(q.Scope))
+			copy(scope, q.Scope)
+			sort.Strings(scope)
 
-	return mapping, nil
-}
-
-// MakeUniq removes duplicated entries.
-func MakeUniq(arr [][]string) [][]string {
-	// remove duplicated
-	m := make(map[string]struct{})
-	for _, elmt := range arr {
-		m[strings.Join(elmt, ",")] = struct{}{}
-	}
-
-	uniq := make([][]string, 0, len(m))
-	for joined := range m {
-		if elmt := strings.Split(joined, ","); len(elmt) > 0 {
-			sort.Strings(elmt)
-			uniq = append(uniq, elmt)
+			key := fmt.Sprintf("%s/%d/%s/%s", q.Metric, ref.ID, strings.Join(scope, scopeTermSeparator), strings.Join(q.GroupBy, ","))
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			mapping[q.Metric] = append(mapping[q.Metric], MonitorScope{
+				Monitor: ref,
+				Scope:   scope,
+				GroupBy: q.GroupBy,
+				Tags:    monitor.GetTags(),
+			})
 		}
 	}
 
-	return uniq
+	return mapping, nil
 }
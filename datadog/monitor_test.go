@@ -3,57 +3,83 @@ package datadog_test
 import (
 	"testing"
 
+	dd "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/terakoya76/modd/datadog"
 )
 
-func Test_MakeUniq(t *testing.T) {
-	cases := []struct {
-		name     string
-		arr      [][]string
-		expected [][]string
-	}{
+func Test_GetMonitorScopesMapping(t *testing.T) {
+	monitors := []dd.Monitor{
 		{
-			name: "when scope is wildcard without tags",
-			arr: [][]string{
-				{"a"},
-				{"a"},
-			},
-			expected: [][]string{
-				{"a"},
-			},
+			Id:    dd.PtrInt64(1),
+			Name:  dd.PtrString("rds cpu prod"),
+			Tags:  []string{"dbengine:postgres"},
+			Query: "avg(last_5m):avg:aws.rds.cpuutilization{env:prod} by {dbinstanceidentifier} > 80",
 		},
 		{
-			name: "when scope is wildcard without tags",
-			arr: [][]string{
-				{"a"},
-				{"b"},
-			},
-			expected: [][]string{
-				{"a"},
-				{"b"},
-			},
+			Id:    dd.PtrInt64(2),
+			Name:  dd.PtrString("rds cpu stg"),
+			Tags:  []string{"dbengine:mysql"},
+			Query: "avg(last_5m):avg:aws.rds.cpuutilization{env:stg} by {dbinstanceidentifier} > 80",
 		},
 		{
-			name: "when scope is wildcard without tags",
-			arr: [][]string{
-				{"a"},
-				{"b"},
-				{"a"},
-				{"b"},
+			Id:    dd.PtrInt64(3),
+			Name:  dd.PtrString("rds cpu prod critical"),
+			Query: "avg(last_5m):avg:aws.rds.cpuutilization{env:prod} by {dbinstanceidentifier} > 90",
+		},
+		{
+			Id:    dd.PtrInt64(4),
+			Name:  dd.PtrString("rds storage"),
+			Query: "avg(last_5m):avg:aws.rds.free_storage_space{*} / avg:aws.rds.total_storage_space{*} < 0.1",
+		},
+		{
+			Id:    dd.PtrInt64(5),
+			Name:  dd.PtrString("rds cpu diff"),
+			Query: "avg(last_5m):avg:aws.rds.cpuutilization{service:a,env:prod} - avg:aws.rds.cpuutilization{env:prod,service:a} > 1",
+		},
+		{
+			Id:    dd.PtrInt64(6),
+			Name:  dd.PtrString("aws status"),
+			Query: `"aws.status".over("*").last(2).count_by_status()`,
+		},
+	}
+
+	cpuProd := datadog.MonitorRef{ID: 1, Name: "rds cpu prod"}
+	cpuStg := datadog.MonitorRef{ID: 2, Name: "rds cpu stg"}
+	cpuProdCritical := datadog.MonitorRef{ID: 3, Name: "rds cpu prod critical"}
+	storage := datadog.MonitorRef{ID: 4, Name: "rds storage"}
+	cpuDiff := datadog.MonitorRef{ID: 5, Name: "rds cpu diff"}
+
+	expected := datadog.MonitorScopesMapping{
+		"aws.rds.cpuutilization": {
+			{
+				Monitor: cpuProd,
+				Scope:   datadog.Scope{"env:prod"},
+				GroupBy: []string{"dbinstanceidentifier"},
+				Tags:    datadog.Tags{"dbengine:postgres"},
 			},
-			expected: [][]string{
-				{"a"},
-				{"b"},
+			{
+				Monitor: cpuStg,
+				Scope:   datadog.Scope{"env:stg"},
+				GroupBy: []string{"dbinstanceidentifier"},
+				Tags:    datadog.Tags{"dbengine:mysql"},
 			},
+			{Monitor: cpuProdCritical, Scope: datadog.Scope{"env:prod"}, GroupBy: []string{"dbinstanceidentifier"}},
+			{Monitor: cpuDiff, Scope: datadog.Scope{"env:prod", "service:a"}, GroupBy: []string{}},
+		},
+		"aws.rds.free_storage_space": {
+			{Monitor: storage, Scope: datadog.Scope{"*"}, GroupBy: []string{}},
+		},
+		"aws.rds.total_storage_space": {
+			{Monitor: storage, Scope: datadog.Scope{"*"}, GroupBy: []string{}},
 		},
 	}
 
-	for _, c := range cases {
-		actual := datadog.MakeUniq(c.arr)
-		if !assert.ElementsMatch(t, c.expected, actual) {
-			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
-		}
+	actual, err := datadog.GetMonitorScopesMapping(monitors)
+	if !assert.Nil(t, err) {
+		t.Fatalf("failed to get monitor scopes mapping: %+v\n", err)
 	}
+
+	assert.Equal(t, expected, actual)
 }
@@ -0,0 +1,83 @@
+package datadog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// metricQueryPattern matches a metric query in a monitor query,
+// e.g. `avg:aws.rds.cpuutilization{env:prod,!host:a} by {dbinstanceidentifier}`.
+var metricQueryPattern = regexp.MustCompile(`([a-zA-Z][\w.]*)\{([^{}]*)\}(?:\s*by\s*\{([^{}]*)\})?`)
+
+// MetricQuery represents a metric query in a Datadog monitor query.
+// GroupBy holds the tag keys of the `by {...}` clause, and is empty for a query without it.
+type MetricQuery struct {
+	Metric  string
+	Scope   Scope
+	GroupBy []string
+}
+
+// ParseMonitorQuery extracts metric queries from a Datadog monitor query.
+// Multi-query formulas, e.g. `avg(last_5m):sum:a{*} by {host} / sum:b{*} by {host} > 1`, produce one MetricQuery per metric.
+// Non-metric queries, e.g. service checks or logs, produce no MetricQuery.
+func ParseMonitorQuery(query string) []MetricQuery {
+	matches := metricQueryPattern.FindAllStringSubmatch(query, -1)
+	queries := make([]MetricQuery, 0, len(matches))
+	for _, match := range matches {
+		queries = append(queries, MetricQuery{
+			Metric:  strings.ToLower(match[1]),
+			Scope:   splitScope(match[2]),
+			GroupBy: splitGroupBy(match[3]),
+		})
+	}
+
+	return queries
+}
+
+// splitScope splits the scope of a metric query into terms by commas outside parentheses.
+// cf. `env:prod,service IN (a,b)` => []string{"env:prod", "service IN (a,b)"}.
+func splitScope(scope string) Scope {
+	terms := make(Scope, 0)
+	depth, start := 0, 0
+	for i, r := range scope {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = appendTerm(terms, scope[start:i])
+				start = i + 1
+			}
+		}
+	}
+	terms = appendTerm(terms, scope[start:])
+
+	if len(terms) == 0 {
+		return Scope{"*"}
+	}
+
+	return terms
+}
+
+// splitGroupBy splits the tag keys of a group by clause.
+// cf. `functionname, region` => []string{"functionname", "region"}.
+func splitGroupBy(groupBy string) []string {
+	keys := make([]string, 0)
+	for _, key := range strings.Split(groupBy, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func appendTerm(terms Scope, term string) Scope {
+	if term = strings.TrimSpace(term); term != "" {
+		return append(terms, term)
+	}
+
+	return terms
+}
@@ -0,0 +1,80 @@
+package datadog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+)
+
+func Test_ParseMonitorQuery(t *testing.T) {
+	cases := []struct {
+		name     string
+		query    string
+		expected []datadog.MetricQuery
+	}{
+		{
+			name:  "when metric alert",
+			query: "avg(last_5m):avg:aws.rds.cpuutilization{env:prod,!dbinstanceidentifier:test-db-1} by {dbinstanceidentifier} > 80",
+			expected: []datadog.MetricQuery{
+				{
+					Metric:  "aws.rds.cpuutilization",
+					Scope:   datadog.Scope{"env:prod", "!dbinstanceidentifier:test-db-1"},
+					GroupBy: []string{"dbinstanceidentifier"},
+				},
+			},
+		},
+		{
+			name:  "when wildcard scope without group by",
+			query: "max(last_1h):sum:aws.sqs.approximate_number_of_messages_visible{*}.as_count() > 100",
+			expected: []datadog.MetricQuery{
+				{
+					Metric:  "aws.sqs.approximate_number_of_messages_visible",
+					Scope:   datadog.Scope{"*"},
+					GroupBy: []string{},
+				},
+			},
+		},
+		{
+			name: "when multi-query formula",
+			query: "sum(last_5m):sum:aws.elb.httpcode_backend_5xx{env:prod} by {loadbalancer}.as_count() / " +
+				"sum:aws.elb.request_count{env:prod} by {loadbalancer}.as_count() > 0.1",
+			expected: []datadog.MetricQuery{
+				{
+					Metric:  "aws.elb.httpcode_backend_5xx",
+					Scope:   datadog.Scope{"env:prod"},
+					GroupBy: []string{"loadbalancer"},
+				},
+				{
+					Metric:  "aws.elb.request_count",
+					Scope:   datadog.Scope{"env:prod"},
+					GroupBy: []string{"loadbalancer"},
+				},
+			},
+		},
+		{
+			name:  "when anomaly monitor with scope grammar",
+			query: "avg(last_4h):anomalies(avg:aws.lambda.duration{env IN (prod,stg) AND service:api} by {functionname,region}, 'basic', 2) >= 1",
+			expected: []datadog.MetricQuery{
+				{
+					Metric:  "aws.lambda.duration",
+					Scope:   datadog.Scope{"env IN (prod,stg) AND service:api"},
+					GroupBy: []string{"functionname", "region"},
+				},
+			},
+		},
+		{
+			name:     "when service check",
+			query:    `"aws.status".over("*").by("region").last(2).count_by_status()`,
+			expected: []datadog.MetricQuery{},
+		},
+	}
+
+	for _, c := range cases {
+		actual := datadog.ParseMonitorQuery(c.query)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
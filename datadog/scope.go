@@ -0,0 +1,396 @@
+package datadog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ScopeExpr represents a parsed Datadog monitor scope expression.
+// cf. `env:prod-* AND (service:api OR service:web) AND host NOT IN (a, b)`.
+// The parser lives in datadog rather than filter so that config can validate the scopes of required metrics
+// at load time, since filter imports config. filter evaluates the parsed expressions with the resource tags.
+type ScopeExpr interface {
+	// Eval returns whether the resource tags satisfy the expression.
+	Eval(tags Tags) bool
+	String() string
+}
+
+// AndExpr represents the conjunction of expressions.
+type AndExpr struct {
+	Exprs []ScopeExpr
+}
+
+// Eval implements ScopeExpr for AndExpr.
+func (e AndExpr) Eval(tags Tags) bool {
+	for _, expr := range e.Exprs {
+		if !expr.Eval(tags) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e AndExpr) String() string {
+	return joinExprs(e.Exprs, " AND ")
+}
+
+// OrExpr represents the disjunction of expressions.
+type OrExpr struct {
+	Exprs []ScopeExpr
+}
+
+// Eval implements ScopeExpr for OrExpr.
+func (e OrExpr) Eval(tags Tags) bool {
+	for _, expr := range e.Exprs {
+		if expr.Eval(tags) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e OrExpr) String() string {
+	return joinExprs(e.Exprs, " OR ")
+}
+
+// NotExpr represents the negation of an expression, e.g. `!env:prod`, `NOT env:prod`.
+type NotExpr struct {
+	Expr ScopeExpr
+}
+
+// Eval implements ScopeExpr for NotExpr.
+func (e NotExpr) Eval(tags Tags) bool {
+	return !e.Expr.Eval(tags)
+}
+
+func (e NotExpr) String() string {
+	switch expr := e.Expr.(type) {
+	case InExpr:
+		return strings.Replace(expr.String(), " IN ", " NOT IN ", 1)
+	case AndExpr, OrExpr:
+		return fmt.Sprintf("!(%s)", expr.String())
+	default:
+		return fmt.Sprintf("!%s", expr.String())
+	}
+}
+
+// TagExpr represents a tag which could contain wildcards, e.g. `env:prod-*`.
+// A tag without value matches any value of the key.
+type TagExpr struct {
+	Tag     string
+	pattern *regexp.Regexp
+}
+
+// NewTagExpr builds TagExpr from the tag.
+func NewTagExpr(tag string) TagExpr {
+	tag = strings.ToLower(tag)
+	if !strings.Contains(tag, ":") && tag != "*" {
+		// `env` matches `env` and `env:<any>`
+		return TagExpr{Tag: tag, pattern: regexp.MustCompile(fmt.Sprintf("^%s(:.*)?$", wildcardToRegexp(tag)))}
+	}
+
+	return TagExpr{Tag: tag, pattern: regexp.MustCompile(fmt.Sprintf("^%s$", wildcardToRegexp(tag)))}
+}
+
+// Eval implements ScopeExpr for TagExpr.
+func (e TagExpr) Eval(tags Tags) bool {
+	if e.Tag == "*" {
+		return true
+	}
+
+	for _, tag := range tags {
+		if e.pattern.MatchString(tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e TagExpr) String() string {
+	return e.Tag
+}
+
+// InExpr represents a set of values of the tag key, e.g. `env IN (prod, stg)`.
+type InExpr struct {
+	Key    string
+	Values []TagExpr
+}
+
+// Eval implements ScopeExpr for InExpr.
+func (e InExpr) Eval(tags Tags) bool {
+	for _, value := range e.Values {
+		if value.Eval(tags) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e InExpr) String() string {
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = strings.TrimPrefix(v.Tag, e.Key+":")
+	}
+
+	return fmt.Sprintf("%s IN (%s)", e.Key, strings.Join(values, ", "))
+}
+
+// ParseScope parses a Datadog scope expression.
+// Comma-separated terms are treated as AND as Datadog does.
+func ParseScope(scope string) (ScopeExpr, error) {
+	p := scopeParser{tokens: tokenizeScope(scope)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty scope")
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q in scope %q", p.tokens[p.pos], scope)
+	}
+
+	return expr, nil
+}
+
+// ParseScopes parses each term of the scope and combines them with AND.
+// A term failed to be parsed is treated as an exact tag.
+// The result is supposed to be reused for every resource evaluated with the scope.
+func ParseScopes(scope []string) AndExpr {
+	exprs := make([]ScopeExpr, 0, len(scope))
+	for _, term := range scope {
+		expr, err := ParseScope(term)
+		if err != nil {
+			expr = TagExpr{Tag: term, pattern: regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(term)))}
+		}
+
+		// flatten nested AND to evaluate each conjunct separately
+		if and, ok := expr.(AndExpr); ok {
+			exprs = append(exprs, and.Exprs...)
+		} else {
+			exprs = append(exprs, expr)
+		}
+	}
+
+	return AndExpr{Exprs: exprs}
+}
+
+type scopeParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *scopeParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *scopeParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *scopeParser) parseOr() (ScopeExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []ScopeExpr{left}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, right)
+	}
+
+	if len(exprs) == 1 {
+		return left, nil
+	}
+
+	return OrExpr{Exprs: exprs}, nil
+}
+
+func (p *scopeParser) parseAnd() (ScopeExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []ScopeExpr{left}
+	for strings.EqualFold(p.peek(), "AND") || p.peek() == "," {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, right)
+	}
+
+	if len(exprs) == 1 {
+		return left, nil
+	}
+
+	return AndExpr{Exprs: exprs}, nil
+}
+
+func (p *scopeParser) parseUnary() (ScopeExpr, error) {
+	if strings.EqualFold(p.peek(), "NOT") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return NotExpr{Expr: expr}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *scopeParser) parsePrimary() (ScopeExpr, error) {
+	t := p.next()
+	switch {
+	case t == "":
+		return nil, fmt.Errorf("unexpected end of scope")
+
+	case t == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+
+		return expr, nil
+
+	case t == ")" || t == "," || isScopeKeyword(t):
+		return nil, fmt.Errorf("unexpected token %q", t)
+
+	case t[0] == '!':
+		if len(t) == 1 {
+			return nil, fmt.Errorf("missing tag after %q", t)
+		}
+
+		return NotExpr{Expr: NewTagExpr(t[1:])}, nil
+	}
+
+	// `key IN (...)` / `key NOT IN (...)`
+	if strings.EqualFold(p.peek(), "IN") {
+		p.next()
+		return p.parseIn(t)
+	}
+
+	if strings.EqualFold(p.peek(), "NOT") && p.pos+1 < len(p.tokens) && strings.EqualFold(p.tokens[p.pos+1], "IN") {
+		p.pos += 2
+		expr, err := p.parseIn(t)
+		if err != nil {
+			return nil, err
+		}
+
+		return NotExpr{Expr: expr}, nil
+	}
+
+	return NewTagExpr(t), nil
+}
+
+func (p *scopeParser) parseIn(key string) (ScopeExpr, error) {
+	if p.next() != "(" {
+		return nil, fmt.Errorf("missing opening parenthesis after IN")
+	}
+
+	key = strings.ToLower(key)
+	values := make([]TagExpr, 0)
+	for {
+		v := p.next()
+		if v == "" || v == "(" || v == ")" || v == "," {
+			return nil, fmt.Errorf("missing value of %s IN", key)
+		}
+		values = append(values, NewTagExpr(fmt.Sprintf("%s:%s", key, v)))
+
+		switch p.next() {
+		case ",":
+			continue
+		case ")":
+			return InExpr{Key: key, Values: values}, nil
+		default:
+			return nil, fmt.Errorf("missing closing parenthesis of %s IN", key)
+		}
+	}
+}
+
+// tokenizeScope splits the scope into parentheses, commas and words.
+func tokenizeScope(scope string) []string {
+	tokens := make([]string, 0)
+	var sb strings.Builder
+
+	flush := func() {
+		if sb.Len() > 0 {
+			tokens = append(tokens, strings.Trim(sb.String(), `"'`))
+			sb.Reset()
+		}
+	}
+
+	for _, r := range scope {
+		switch r {
+		case ' ', '\t', '\n':
+			flush()
+		case '(', ')', ',':
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func isScopeKeyword(t string) bool {
+	for _, k := range []string{"AND", "OR", "NOT", "IN"} {
+		if strings.EqualFold(t, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func wildcardToRegexp(s string) string {
+	parts := strings.Split(s, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return strings.Join(parts, ".*")
+}
+
+func joinExprs(exprs []ScopeExpr, sep string) string {
+	strs := make([]string, len(exprs))
+	for i, expr := range exprs {
+		switch expr.(type) {
+		case AndExpr, OrExpr:
+			strs[i] = fmt.Sprintf("(%s)", expr.String())
+		default:
+			strs[i] = expr.String()
+		}
+	}
+
+	return strings.Join(strs, sep)
+}
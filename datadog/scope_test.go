@@ -0,0 +1,198 @@
+package datadog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+)
+
+func Test_ParseScope(t *testing.T) {
+	cases := []struct {
+		name     string
+		scope    string
+		expected string
+		err      string
+	}{
+		{
+			name:     "when single tag",
+			scope:    "env:prod",
+			expected: "env:prod",
+			err:      "",
+		},
+		{
+			name:     "when comma-separated tags",
+			scope:    "env:prod,!host:a",
+			expected: "env:prod AND !host:a",
+			err:      "",
+		},
+		{
+			name:     "when OR has lower precedence than AND",
+			scope:    "env:prod AND service:api OR service:web",
+			expected: "(env:prod AND service:api) OR service:web",
+			err:      "",
+		},
+		{
+			name:     "when grouped with parentheses",
+			scope:    "env:prod AND (service:api OR service:web)",
+			expected: "env:prod AND (service:api OR service:web)",
+			err:      "",
+		},
+		{
+			name:     "when IN and NOT IN lists",
+			scope:    "env IN (prod, stg) and NOT host not in (a)",
+			expected: "env IN (prod, stg) AND !host NOT IN (a)",
+			err:      "",
+		},
+		{
+			name:     "when keyword is lowercase and value is uppercase",
+			scope:    "Env:Prod or env:stg",
+			expected: "env:prod OR env:stg",
+			err:      "",
+		},
+		{
+			name:     "when tag starts with hyphen",
+			scope:    "-env:dev",
+			expected: "-env:dev",
+			err:      "",
+		},
+		{
+			name:     "when empty scope",
+			scope:    " ",
+			expected: "",
+			err:      "empty scope",
+		},
+		{
+			name:     "when parenthesis is not closed",
+			scope:    "(env:prod OR env:stg",
+			expected: "",
+			err:      "missing closing parenthesis",
+		},
+		{
+			name:     "when IN list is empty",
+			scope:    "env IN ()",
+			expected: "",
+			err:      "missing value of env IN",
+		},
+		{
+			name:     "when operator lacks operand",
+			scope:    "env:prod AND",
+			expected: "",
+			err:      "unexpected end of scope",
+		},
+		{
+			name:     "when extra token remains",
+			scope:    "env:prod)",
+			expected: "",
+			err:      `unexpected token ")" in scope "env:prod)"`,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := datadog.ParseScope(c.scope)
+		if c.err != "" {
+			if !assert.EqualError(t, err, c.err) {
+				t.Errorf("case: %s is failed, expected: %s, actual: %+v\n", c.name, c.err, err)
+			}
+			continue
+		}
+
+		if !assert.Nil(t, err) {
+			t.Errorf("case: %s is failed, err: %+v\n", c.name, err)
+			continue
+		}
+		if !assert.Equal(t, c.expected, actual.String()) {
+			t.Errorf("case: %s is failed, expected: %s, actual: %s\n", c.name, c.expected, actual.String())
+		}
+	}
+}
+
+func Test_ScopeExpr_Eval(t *testing.T) {
+	cases := []struct {
+		name     string
+		scope    string
+		tags     datadog.Tags
+		expected bool
+	}{
+		{
+			name:     "when wildcard",
+			scope:    "*",
+			tags:     []string{},
+			expected: true,
+		},
+		{
+			name:     "when wildcard value matches",
+			scope:    "env:prod-*",
+			tags:     []string{"env:prod-tokyo"},
+			expected: true,
+		},
+		{
+			name:     "when wildcard value does not match",
+			scope:    "env:prod-*",
+			tags:     []string{"env:production"},
+			expected: false,
+		},
+		{
+			name:     "when wildcard matches a value containing slash",
+			scope:    "loadbalancer:app/*",
+			tags:     []string{"loadbalancer:app/web/0123456789abcdef"},
+			expected: true,
+		},
+		{
+			name:     "when tag key without value",
+			scope:    "env",
+			tags:     []string{"env:prod"},
+			expected: true,
+		},
+		{
+			name:     "when regexp meta characters are quoted",
+			scope:    "name:a.b",
+			tags:     []string{"name:axb"},
+			expected: false,
+		},
+		{
+			name:     "when IN list matches",
+			scope:    "env IN (prod, stg-*)",
+			tags:     []string{"env:stg-1"},
+			expected: true,
+		},
+		{
+			name:     "when NOT IN list matches",
+			scope:    "env NOT IN (prod, stg)",
+			tags:     []string{"env:prod"},
+			expected: false,
+		},
+		{
+			name:     "when AND is satisfied partially",
+			scope:    "env:prod AND service:api",
+			tags:     []string{"env:prod", "service:web"},
+			expected: false,
+		},
+		{
+			name:     "when grouped OR is satisfied",
+			scope:    "env:prod AND (service:api OR service:web)",
+			tags:     []string{"env:prod", "service:web"},
+			expected: true,
+		},
+		{
+			name:     "when negated group is satisfied",
+			scope:    "NOT (env:dev OR env:stg)",
+			tags:     []string{"env:prod"},
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		expr, err := datadog.ParseScope(c.scope)
+		if !assert.Nil(t, err) {
+			t.Errorf("case: %s is failed, err: %+v\n", c.name, err)
+			continue
+		}
+
+		actual := expr.Eval(c.tags)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %t, actual: %t\n", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/terakoya76/modd/report"
+)
+
+// runDiff compares two saved scan results, or a saved scan result with a live scan.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	var cf commonFlags
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	cf.register(fs)
+	cf.registerFormat(fs)
+	cf.registerScan(fs)
+	cf.registerBaseline(fs)
+	integrations := fs.String("integrations", "", "comma-separated integrations to be scanned, e.g. aws_rds,aws_sqs (all by default)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: modd diff [flags] <old result> [<new result>]\n\n")
+		fmt.Fprintf(fs.Output(), "The new result is scanned live when omitted.\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return 1
+	}
+
+	its, err := parseIntegrations(*integrations)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	oldScan, err := report.LoadScan(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	var newScan report.Scan
+	if fs.NArg() == 2 {
+		newScan, err = report.LoadScan(fs.Arg(1))
+	} else {
+		ctx, cancel := cf.context()
+		defer cancel()
+
+		newScan, err = runScanner(ctx, cfg, its, cf.concurrency, stderr)
+		if err == nil {
+			err = applyBaseline(cfg, &newScan, stderr)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	diff := report.CompareScans(oldScan, newScan)
+	if err := report.WriteDiff(stdout, diff, cfg.Output.Format, cfg.Output.Pretty); err != nil {
+		fmt.Fprintf(stderr, "failed to write diff: %v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	return 0
+}
@@ -6,6 +6,7 @@ import (
 
 	"golang.org/x/sync/singleflight"
 
+	"github.com/terakoya76/modd/config"
 	"github.com/terakoya76/modd/datadog"
 	"github.com/terakoya76/modd/filter"
 	"github.com/terakoya76/modd/mapper"
@@ -21,62 +22,194 @@ type Evaluator struct {
 }
 
 // BuildEvaluator build the proper Evaluator implementation.
-func BuildEvaluator(it datadog.IntegrationTarget) (Evaluator, error) {
-	f, err := filter.BuildFilter(it)
+// A failure to build TagsMapper, e.g. to resolve the regions or the accounts, is returned as TagsMappingError.
+func BuildEvaluator(ctx context.Context, it datadog.IntegrationTarget, ic config.IntegrationConfig) (Evaluator, error) {
+	f, err := filter.BuildFilter(it, ic)
 	if err != nil {
-		return Evaluator{}, fmt.Errorf("failed to get Filter object")
+		return Evaluator{}, fmt.Errorf("failed to get Filter object: %w", err)
 	}
 
-	m, err := mapper.BuildTagsMapper(it)
+	m, err := mapper.BuildTagsMapper(ctx, it, ic)
 	if err != nil {
-		return Evaluator{}, fmt.Errorf("failed to get TagsMapper object")
+		return Evaluator{}, &TagsMappingError{Integration: it, Err: fmt.Errorf("failed to get TagsMapper object: %w", err)}
 	}
 
-	e := Evaluator{
+	return NewEvaluator(it, f, m), nil
+}
+
+// NewEvaluator returns Evaluator with the given Filter and TagsMapper.
+func NewEvaluator(it datadog.IntegrationTarget, f filter.Filter, m mapper.TagsMapper) Evaluator {
+	return Evaluator{
 		it:        it,
 		filter:    f,
 		tagMapper: m,
 	}
+}
 
-	return e, nil
+// TagsMappingError represents a failure to fetch the resources of the integration.
+// The failure is limited to the integration, and the other integrations are still evaluated.
+type TagsMappingError struct {
+	Integration datadog.IntegrationTarget
+	Err         error
 }
 
-// Evaluate returns a list of unmonitored resource identifiers.
-func (e Evaluator) Evaluate(ctx context.Context, scopes []datadog.Scope, ddTags datadog.Tags) ([]string, error) {
-	name := string(e.it)
-	v, err, _ := group.Do(name, func() (interface{}, error) {
-		return e.tagMapper.GetTagsMapping(ctx)
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get resource tags mapping: %w", err)
-	}
+func (e *TagsMappingError) Error() string {
+	return fmt.Sprintf("failed to get resource tags mapping: %v", e.Err)
+}
 
-	mapping, ok := v.(map[string][]string)
-	if !ok {
-		return nil, fmt.Errorf("failed type assertion: %w", err)
+func (e *TagsMappingError) Unwrap() error {
+	return e.Err
+}
+
+// Result represents the evaluation of a metric.
+// Resources holds every resource identifier of the integration,
+// Excluded holds the ones ignored or explicitly excluded from the monitors,
+// Monitored maps each monitored resource identifier into the monitors covering it,
+// Violations holds the unmonitored ones for which the metric is required,
+// and Stale holds the monitor scopes matching no live resource.
+type Result struct {
+	Resources   []string
+	Unmonitored []string
+	Excluded    []string
+	Monitored   map[string][]datadog.MonitorRef
+	Violations  []string
+	Stale       []datadog.MonitorScope
+}
+
+// Evaluate returns unmonitored resource identifiers and the monitors covering each monitored resource of the metric.
+// A monitor covers only the resources whose tags are matched with its own tags by Filter.CheckTagsWithTags.
+// scopes could be empty when no monitor exists for the required metric,
+// and then only the resources for which the metric is required are reported as unmonitored.
+func (e Evaluator) Evaluate(ctx context.Context, metric string, scopes []datadog.MonitorScope) (Result, error) {
+	mapping, err := e.getTagsMapping(ctx)
+	if err != nil {
+		return Result{}, err
 	}
 
 	identifiers := GetIdentifiersFromMaaping(mapping)
 	monitoredIdents := make([]string, 0, len(mapping))
 	excludedIdents := make([]string, 0, len(mapping))
+	coverage := make(map[string][]datadog.MonitorRef)
+	requiredIdents := make([]string, 0)
+	exprs := parseScopes(scopes)
 
 	for id, resourceTags := range mapping {
-		for _, scope := range scopes {
-			monitored, excluded := e.filter.CheckScopeWithTags(scope, resourceTags)
+		if e.filter.CheckIgnored(id, resourceTags) {
+			excludedIdents = append(excludedIdents, id)
+			continue
+		}
+
+		if e.filter.CheckRequired(metric, resourceTags) {
+			requiredIdents = append(requiredIdents, id)
+		} else if len(scopes) == 0 {
+			// the metric without monitors is evaluated only for the resources required by the policy
+			excludedIdents = append(excludedIdents, id)
+			continue
+		}
+
+		tagsMatched := false
+		for i, ms := range scopes {
+			if !e.filter.CheckTagsWithTags(ms.Tags, resourceTags) {
+				continue
+			}
+			tagsMatched = true
+
+			monitored, excluded := e.filter.CheckScopeWithTags(exprs[i], resourceTags)
 			if monitored {
 				monitoredIdents = append(monitoredIdents, id)
+				coverage[id] = appendMonitorRef(coverage[id], ms.Monitor)
 			}
 			if excluded {
 				excludedIdents = append(excludedIdents, id)
 			}
 		}
 
-		if !e.filter.CheckTagsWithTags(ddTags, resourceTags) {
+		// nothing to be matched with resource tags without monitors
+		if len(scopes) > 0 && !tagsMatched {
 			excludedIdents = append(excludedIdents, id)
 		}
 	}
 
-	return filter.Difference(filter.Difference(identifiers, monitoredIdents), excludedIdents), nil
+	unmonitored := filter.Difference(filter.Difference(identifiers, monitoredIdents), excludedIdents)
+	result := Result{
+		Resources:   identifiers,
+		Unmonitored: unmonitored,
+		Excluded:    filter.Intersect(excludedIdents, identifiers),
+		Monitored:   coverage,
+		Violations:  filter.Intersect(requiredIdents, unmonitored),
+		Stale:       e.getStaleScopes(scopes, exprs, mapping),
+	}
+
+	return result, nil
+}
+
+// getTagsMapping returns the resource tags mapping shared by the concurrent evaluations of the integration.
+// A failure is returned as TagsMappingError.
+func (e Evaluator) getTagsMapping(ctx context.Context) (map[string][]string, error) {
+	name := string(e.it)
+	v, err, _ := group.Do(name, func() (interface{}, error) {
+		return e.tagMapper.GetTagsMapping(ctx)
+	})
+	if err != nil {
+		return nil, &TagsMappingError{Integration: e.it, Err: err}
+	}
+
+	mapping, ok := v.(map[string][]string)
+	if !ok {
+		return nil, fmt.Errorf("failed type assertion: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// getStaleScopes returns the monitor scopes matching no resource whose tags are matched with the monitor tags.
+// exprs are the scopes parsed by parseScopes.
+// Ignored resources are also matched since they are still alive.
+func (e Evaluator) getStaleScopes(
+	scopes []datadog.MonitorScope, exprs []datadog.AndExpr, mapping map[string][]string,
+) []datadog.MonitorScope {
+	stale := make([]datadog.MonitorScope, 0)
+	for i, ms := range scopes {
+		matched := false
+		for _, resourceTags := range mapping {
+			if !e.filter.CheckTagsWithTags(ms.Tags, resourceTags) {
+				continue
+			}
+
+			if included, _ := e.filter.CheckScopeWithTags(exprs[i], resourceTags); included {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			stale = append(stale, ms)
+		}
+	}
+
+	return stale
+}
+
+// parseScopes parses each monitor scope once to be evaluated with every resource.
+func parseScopes(scopes []datadog.MonitorScope) []datadog.AndExpr {
+	exprs := make([]datadog.AndExpr, len(scopes))
+	for i, ms := range scopes {
+		exprs[i] = datadog.ParseScopes(ms.Scope)
+	}
+
+	return exprs
+}
+
+// appendMonitorRef appends the monitor unless it is already included.
+// A monitor could cover the resource with more than one scope.
+func appendMonitorRef(refs []datadog.MonitorRef, ref datadog.MonitorRef) []datadog.MonitorRef {
+	for _, r := range refs {
+		if r.ID == ref.ID {
+			return refs
+		}
+	}
+
+	return append(refs, ref)
 }
 
 // GetIdentifiersFromMaaping returns a list of identifiers from mapping keys.
@@ -1,11 +1,17 @@
 package evaluator_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
 	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/filter"
+	"github.com/terakoya76/modd/mapper"
 )
 
 func Test_GetIdentifiersFromMaaping(t *testing.T) {
@@ -31,3 +37,202 @@ func Test_GetIdentifiersFromMaaping(t *testing.T) {
 		}
 	}
 }
+
+type dummyTagsMapper struct {
+	mapping map[string]mapper.Tags
+	err     error
+}
+
+func (m dummyTagsMapper) GetTagsMapping(ctx context.Context) (map[string]mapper.Tags, error) {
+	return m.mapping, m.err
+}
+
+func Test_Evaluate(t *testing.T) {
+	prod := datadog.MonitorRef{ID: 1, Name: "rds cpu prod"}
+	all := datadog.MonitorRef{ID: 2, Name: "rds cpu all"}
+	sandbox := datadog.MonitorRef{ID: 3, Name: "sqs sandbox"}
+	postgres := datadog.MonitorRef{ID: 4, Name: "rds cpu postgres"}
+	mysql := datadog.MonitorRef{ID: 5, Name: "rds cpu mysql"}
+
+	cases := []struct {
+		name     string
+		it       datadog.IntegrationTarget
+		metric   string
+		ic       config.IntegrationConfig
+		mapping  map[string]mapper.Tags
+		scopes   []datadog.MonitorScope
+		expected evaluator.Result
+	}{
+		{
+			name:   "when resources are covered by different monitors",
+			it:     datadog.AwsRds,
+			metric: "aws.rds.cpuutilization",
+			ic:     config.IntegrationConfig{},
+			mapping: map[string]mapper.Tags{
+				"db-1": {"env:prod"},
+				"db-2": {"env:stg"},
+			},
+			scopes: []datadog.MonitorScope{
+				{Monitor: prod, Scope: datadog.Scope{"env:prod"}},
+				{Monitor: all, Scope: datadog.Scope{"*"}},
+				{Monitor: all, Scope: datadog.Scope{"env:dev"}},
+			},
+			expected: evaluator.Result{
+				Resources:   []string{"db-1", "db-2"},
+				Unmonitored: []string{},
+				Excluded:    []string{},
+				Monitored: map[string][]datadog.MonitorRef{
+					"db-1": {prod, all},
+					"db-2": {all},
+				},
+				Stale: []datadog.MonitorScope{
+					{Monitor: all, Scope: datadog.Scope{"env:dev"}},
+				},
+			},
+		},
+		{
+			name:   "when resources are unmonitored or ignored",
+			it:     datadog.AwsSqs,
+			metric: "aws.sqs.number_of_messages_sent",
+			ic: config.IntegrationConfig{
+				Ignore: []config.IgnoreRule{{Resource: "sandbox-*"}},
+			},
+			mapping: map[string]mapper.Tags{
+				"queue-1":       {"env:prod"},
+				"queue-2":       {"env:stg"},
+				"sandbox-queue": {"env:sandbox"},
+			},
+			scopes: []datadog.MonitorScope{
+				{Monitor: sandbox, Scope: datadog.Scope{"env:sandbox"}},
+				{Monitor: prod, Scope: datadog.Scope{"env:prod"}},
+				{Monitor: prod, Scope: datadog.Scope{"env:prod", "!queuename:x"}},
+			},
+			expected: evaluator.Result{
+				Resources:   []string{"queue-1", "queue-2", "sandbox-queue"},
+				Unmonitored: []string{"queue-2"},
+				Excluded:    []string{"sandbox-queue"},
+				Monitored: map[string][]datadog.MonitorRef{
+					"queue-1": {prod},
+				},
+			},
+		},
+		{
+			name:   "when monitors are split by tags",
+			it:     datadog.AwsRds,
+			metric: "aws.rds.cpuutilization",
+			ic: config.IntegrationConfig{
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "engine", DdTagKey: "dbengine", Values: map[string]string{"aurora-postgresql": "postgres"}},
+				},
+			},
+			mapping: map[string]mapper.Tags{
+				"db-1": {"engine:aurora-postgresql"},
+				"db-2": {"engine:mysql"},
+				"db-3": {"engine:oracle"},
+			},
+			scopes: []datadog.MonitorScope{
+				{Monitor: postgres, Scope: datadog.Scope{"*"}, Tags: datadog.Tags{"dbengine:postgres"}},
+				{Monitor: mysql, Scope: datadog.Scope{"*"}, Tags: datadog.Tags{"dbengine:mysql"}},
+				{Monitor: mysql, Scope: datadog.Scope{"env:prod"}, Tags: datadog.Tags{"dbengine:mysql"}},
+			},
+			expected: evaluator.Result{
+				Resources:   []string{"db-1", "db-2", "db-3"},
+				Unmonitored: []string{},
+				Excluded:    []string{"db-3"},
+				Monitored: map[string][]datadog.MonitorRef{
+					"db-1": {postgres},
+					"db-2": {mysql},
+				},
+				Stale: []datadog.MonitorScope{
+					{Monitor: mysql, Scope: datadog.Scope{"env:prod"}, Tags: datadog.Tags{"dbengine:mysql"}},
+				},
+			},
+		},
+		{
+			name:   "when required metric has no monitor",
+			it:     datadog.AwsRds,
+			metric: "aws.rds.free_storage_space",
+			ic: config.IntegrationConfig{
+				TagKeys: []config.TagKeyPair{{AwsTagKey: "env", DdTagKey: "env"}},
+				RequiredMetrics: []config.RequiredMetricsRule{
+					{Metrics: []string{"aws.rds.free_storage_space"}, Scope: "env:production"},
+				},
+			},
+			mapping: map[string]mapper.Tags{
+				"db-1": {"env:production"},
+				"db-2": {"env:stg"},
+			},
+			scopes: []datadog.MonitorScope{},
+			expected: evaluator.Result{
+				Resources:   []string{"db-1", "db-2"},
+				Unmonitored: []string{"db-1"},
+				Excluded:    []string{"db-2"},
+				Monitored:   map[string][]datadog.MonitorRef{},
+				Violations:  []string{"db-1"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		f, err := filter.BuildFilter(c.it, c.ic)
+		if err != nil {
+			t.Fatalf("failed to build filter: %+v\n", err)
+		}
+
+		e := evaluator.NewEvaluator(c.it, f, dummyTagsMapper{mapping: c.mapping})
+		actual, err := e.Evaluate(context.Background(), c.metric, c.scopes)
+		if !assert.Nil(t, err) {
+			t.Errorf("case: %s is failed, err: %+v\n", c.name, err)
+			continue
+		}
+
+		if !assert.ElementsMatch(t, c.expected.Resources, actual.Resources) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected.Resources, actual.Resources)
+		}
+		if !assert.ElementsMatch(t, c.expected.Unmonitored, actual.Unmonitored) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected.Unmonitored, actual.Unmonitored)
+		}
+		if !assert.ElementsMatch(t, c.expected.Excluded, actual.Excluded) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected.Excluded, actual.Excluded)
+		}
+		if !assert.ElementsMatch(t, c.expected.Violations, actual.Violations) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected.Violations, actual.Violations)
+		}
+		if !assert.ElementsMatch(t, c.expected.Stale, actual.Stale) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected.Stale, actual.Stale)
+		}
+		if !assert.Equal(t, c.expected.Monitored, actual.Monitored) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected.Monitored, actual.Monitored)
+		}
+	}
+}
+
+func Test_EvaluateTagsMappingError(t *testing.T) {
+	f, err := filter.BuildFilter(datadog.AwsRds, config.IntegrationConfig{})
+	if err != nil {
+		t.Fatalf("failed to build filter: %+v\n", err)
+	}
+
+	cause := errors.New("access denied")
+	e := evaluator.NewEvaluator(datadog.AwsRds, f, dummyTagsMapper{err: cause})
+	_, err = e.Evaluate(context.Background(), "aws.rds.cpuutilization", []datadog.MonitorScope{})
+
+	var tmErr *evaluator.TagsMappingError
+	if !assert.True(t, errors.As(err, &tmErr)) {
+		t.Fatalf("unexpected error: %+v\n", err)
+	}
+	assert.Equal(t, datadog.AwsRds, tmErr.Integration)
+	assert.True(t, errors.Is(err, cause))
+}
+
+func Test_BuildEvaluatorTagsMappingError(t *testing.T) {
+	ic := config.IntegrationConfig{Accounts: []string{"not-an-account"}}
+	_, err := evaluator.BuildEvaluator(context.Background(), datadog.AwsRds, ic)
+
+	var tmErr *evaluator.TagsMappingError
+	if !assert.True(t, errors.As(err, &tmErr)) {
+		t.Fatalf("unexpected error: %+v\n", err)
+	}
+	assert.Equal(t, datadog.AwsRds, tmErr.Integration)
+	assert.Contains(t, err.Error(), "invalid AWS account ID or role ARN: not-an-account")
+}
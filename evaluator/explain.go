@@ -0,0 +1,108 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/mapper"
+)
+
+// Explanation represents how a resource is evaluated against the monitors of each metric.
+type Explanation struct {
+	Integration datadog.IntegrationTarget
+	Resource    string
+	Tags        mapper.Tags
+	Ignored     bool
+	Metrics     []MetricExplanation
+}
+
+// MetricExplanation represents how a resource is evaluated against the monitors of a metric.
+// The resource is unmonitored unless it is either Monitored or Excluded.
+type MetricExplanation struct {
+	Metric    string
+	Required  bool
+	Scopes    []ScopeExplanation
+	Monitored bool
+	Excluded  bool
+}
+
+// ScopeExplanation represents the decisions of Filter.CheckTagsWithTags with the monitor tags
+// and Filter.CheckScopeWithTags for a monitor scope.
+// The scope is taken into account only when TagsMatched is true.
+type ScopeExplanation struct {
+	Monitor     datadog.MonitorRef
+	Scope       datadog.Scope
+	GroupBy     []string
+	MonitorTags datadog.Tags
+	TagsMatched bool
+	Included    bool
+	Excluded    bool
+}
+
+// Explain evaluates the resource against every monitor scope of the metrics step by step.
+// The decisions are the same as Evaluate.
+func (e Evaluator) Explain(
+	ctx context.Context, id string, monitorScopesMapping datadog.MonitorScopesMapping,
+) (Explanation, error) {
+	mapping, err := e.getTagsMapping(ctx)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	resourceTags, ok := mapping[id]
+	if !ok {
+		return Explanation{}, fmt.Errorf("resource %q is not found in %s", id, e.it)
+	}
+
+	explanation := Explanation{
+		Integration: e.it,
+		Resource:    id,
+		Tags:        resourceTags,
+		Ignored:     e.filter.CheckIgnored(id, resourceTags),
+		Metrics:     make([]MetricExplanation, 0),
+	}
+
+	for metric, scopes := range monitorScopesMapping {
+		if datadog.MetricToIntegrationTarget(metric) != e.it {
+			continue
+		}
+
+		me := MetricExplanation{
+			Metric:   metric,
+			Required: e.filter.CheckRequired(metric, resourceTags),
+			Scopes:   make([]ScopeExplanation, 0, len(scopes)),
+			Excluded: explanation.Ignored,
+		}
+
+		tagsMatched := false
+		for i, expr := range parseScopes(scopes) {
+			ms := scopes[i]
+			se := ScopeExplanation{
+				Monitor:     ms.Monitor,
+				Scope:       ms.Scope,
+				GroupBy:     ms.GroupBy,
+				MonitorTags: ms.Tags,
+				TagsMatched: e.filter.CheckTagsWithTags(ms.Tags, resourceTags),
+			}
+			se.Included, se.Excluded = e.filter.CheckScopeWithTags(expr, resourceTags)
+			me.Scopes = append(me.Scopes, se)
+
+			if se.TagsMatched {
+				tagsMatched = true
+				me.Monitored = me.Monitored || se.Included
+				me.Excluded = me.Excluded || se.Excluded
+			}
+		}
+		me.Excluded = me.Excluded || (len(scopes) > 0 && !tagsMatched) || (len(scopes) == 0 && !me.Required)
+
+		explanation.Metrics = append(explanation.Metrics, me)
+	}
+
+	sort.Slice(explanation.Metrics, func(i, j int) bool {
+		return explanation.Metrics[i].Metric < explanation.Metrics[j].Metric
+	})
+
+	return explanation, nil
+}
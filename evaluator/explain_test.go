@@ -0,0 +1,114 @@
+package evaluator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/filter"
+	"github.com/terakoya76/modd/mapper"
+)
+
+func Test_Explain(t *testing.T) {
+	prod := datadog.MonitorRef{ID: 1, Name: "rds cpu prod"}
+	nonDev := datadog.MonitorRef{ID: 2, Name: "rds storage except dev"}
+
+	ic := config.IntegrationConfig{
+		TagKeys: []config.TagKeyPair{{AwsTagKey: "engine", DdTagKey: "dbengine"}},
+		RequiredMetrics: []config.RequiredMetricsRule{
+			{Metrics: []string{"aws.rds.database_connections"}},
+		},
+	}
+	f, err := filter.BuildFilter(datadog.AwsRds, ic)
+	if err != nil {
+		t.Fatalf("failed to build filter: %+v\n", err)
+	}
+
+	m := dummyTagsMapper{mapping: map[string]mapper.Tags{
+		"db-1": {"env:dev", "engine:mysql"},
+	}}
+	e := evaluator.NewEvaluator(datadog.AwsRds, f, m)
+
+	monitorScopesMapping := datadog.MonitorScopesMapping{
+		"aws.rds.cpuutilization": {
+			{Monitor: prod, Scope: datadog.Scope{"env:prod"}, GroupBy: []string{"dbinstanceidentifier"}, Tags: datadog.Tags{"dbengine:mysql"}},
+		},
+		"aws.rds.free_storage_space": {
+			{Monitor: nonDev, Scope: datadog.Scope{"!env:dev"}, Tags: datadog.Tags{"dbengine:postgres"}},
+		},
+		"aws.rds.database_connections": {},
+		"aws.rds.replica_lag":          {},
+		"aws.sqs.number_of_messages_sent": {
+			{Monitor: prod, Scope: datadog.Scope{"*"}},
+		},
+	}
+
+	expected := evaluator.Explanation{
+		Integration: datadog.AwsRds,
+		Resource:    "db-1",
+		Tags:        mapper.Tags{"env:dev", "engine:mysql"},
+		Ignored:     false,
+		Metrics: []evaluator.MetricExplanation{
+			{
+				Metric:   "aws.rds.cpuutilization",
+				Required: false,
+				Scopes: []evaluator.ScopeExplanation{
+					{
+						Monitor:     prod,
+						Scope:       datadog.Scope{"env:prod"},
+						GroupBy:     []string{"dbinstanceidentifier"},
+						MonitorTags: datadog.Tags{"dbengine:mysql"},
+						TagsMatched: true,
+						Included:    false,
+						Excluded:    false,
+					},
+				},
+				Monitored: false,
+				Excluded:  false,
+			},
+			{
+				Metric:    "aws.rds.database_connections",
+				Required:  true,
+				Scopes:    []evaluator.ScopeExplanation{},
+				Monitored: false,
+				Excluded:  false,
+			},
+			{
+				Metric:   "aws.rds.free_storage_space",
+				Required: false,
+				Scopes: []evaluator.ScopeExplanation{
+					{
+						Monitor:     nonDev,
+						Scope:       datadog.Scope{"!env:dev"},
+						MonitorTags: datadog.Tags{"dbengine:postgres"},
+						TagsMatched: false,
+						Included:    false,
+						Excluded:    true,
+					},
+				},
+				Monitored: false,
+				Excluded:  true,
+			},
+			{
+				Metric:    "aws.rds.replica_lag",
+				Required:  false,
+				Scopes:    []evaluator.ScopeExplanation{},
+				Monitored: false,
+				Excluded:  true,
+			},
+		},
+	}
+
+	actual, err := e.Explain(context.Background(), "db-1", monitorScopesMapping)
+	if !assert.Nil(t, err) {
+		t.Fatalf("failed to explain: %+v\n", err)
+	}
+	assert.Equal(t, expected, actual)
+
+	_, err = e.Explain(context.Background(), "db-2", monitorScopesMapping)
+	assert.EqualError(t, err, `resource "db-2" is not found in aws_rds`)
+}
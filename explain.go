@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+// runExplain explains how the resource is evaluated against every monitor of the integration.
+func runExplain(args []string, stdout, stderr io.Writer) int {
+	var cf commonFlags
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	cf.register(fs)
+	cf.registerPretty(fs)
+	cf.registerScan(fs)
+	integration := fs.String("integration", "", "integration of the resource, e.g. aws_rds")
+	resource := fs.String("resource", "", "resource identifier, e.g. test-db-1")
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	it := datadog.IntegrationTarget(*integration)
+	if !datadog.IsSupportedIntegrationTarget(it) || *resource == "" {
+		fmt.Fprintf(stderr, "both supported -integration and -resource are required\n")
+		fs.Usage()
+		return 1
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	ddMonitorScopesMapping, err := getMonitorScopesMapping(ctx, cfg, cf.concurrency)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	e, err := evaluator.BuildEvaluator(ctx, it, cfg.Integration(it))
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to get Evaluator object: %v\n", err)
+		return 1
+	}
+
+	explanation, err := e.Explain(ctx, *resource, withRequiredMetrics(cfg, ddMonitorScopesMapping))
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to explain: %v\n", err)
+		return 1
+	}
+
+	if err := report.WriteExplanation(stdout, explanation, cfg.Output.Pretty); err != nil {
+		fmt.Fprintf(stderr, "failed to write result: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -1,8 +1,10 @@
 package filter
 
 import (
+	"path"
 	"strings"
 
+	"github.com/terakoya76/modd/config"
 	"github.com/terakoya76/modd/datadog"
 	"github.com/terakoya76/modd/mapper"
 )
@@ -10,61 +12,127 @@ import (
 // AwsFilter implements Filter interface.
 // it holds the metadata to filter AWS resources which should be monitored.
 type AwsFilter struct {
-	AwsTagKey string
-	DdTagKey  string
+	TagKeys  []config.TagKeyPair
+	TagMatch string
+	Ignore   []config.IgnoreRule
+	Required []RequiredMetrics
 }
 
-// CheckScopeWithTags evaluates Datadog scope and AWS resources.
-func (af AwsFilter) CheckScopeWithTags(scope datadog.Scope, tags mapper.Tags) (included, excluded bool) {
-	wildcard := false
-	matchers := make([]string, 0, len(scope))
-	inverted := make([]string, 0, len(scope))
-	for _, matcher := range scope {
-		if matcher[0] == '!' {
-			inverted = append(inverted, matcher[1:])
-		} else if matcher == "*" {
-			wildcard = true
-		} else {
-			matchers = append(matchers, matcher)
+// CheckScopeWithTags evaluates Datadog scope parsed by datadog.ParseScopes and AWS resources.
+// The resource is excluded when it matches any negated term explicitly, e.g. `!env:dev`, `env NOT IN (dev)`.
+func (af AwsFilter) CheckScopeWithTags(scope datadog.AndExpr, tags mapper.Tags) (included, excluded bool) {
+	for _, term := range scope.Exprs {
+		if not, ok := term.(datadog.NotExpr); ok && not.Expr.Eval(tags) {
+			return false, true
 		}
 	}
 
-	if len(Intersect(inverted, tags)) > 0 {
-		return false, true
+	return scope.Eval(tags), false
+}
+
+// CheckTagsWithTags evaluates Datadog/AWS tag matchers.
+// All the tag key pairs must be matched by default, and any of them when TagMatch is `any`.
+func (af AwsFilter) CheckTagsWithTags(ddTags datadog.Tags, resourceTags mapper.Tags) bool {
+	pairs := make([]config.TagKeyPair, 0, len(af.TagKeys))
+	for _, pair := range af.TagKeys {
+		if pair.AwsTagKey != "" && pair.DdTagKey != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	if len(pairs) == 0 {
+		return true
+	}
+
+	if af.TagMatch == config.TagMatchAny {
+		for _, pair := range pairs {
+			if checkTagKeyPair(pair, ddTags, resourceTags) {
+				return true
+			}
+		}
+
+		return false
 	}
 
-	if wildcard {
-		return true, false
+	for _, pair := range pairs {
+		if !checkTagKeyPair(pair, ddTags, resourceTags) {
+			return false
+		}
 	}
 
-	if len(Difference(matchers, tags)) == 0 {
-		return true, false
+	return true
+}
+
+// CheckIgnored evaluates whether the resource matches any of ignore rules.
+// A resource pattern is matched with both the identifier qualified with the account and region, e.g. `123456789012/us-east-1/test-db-1`,
+// and the unqualified one, e.g. `test-db-1`, so that the same rules work when more than one account or region is scanned.
+func (af AwsFilter) CheckIgnored(id string, resourceTags mapper.Tags) bool {
+	unqualified := unqualifyID(id, resourceTags)
+	for _, rule := range af.Ignore {
+		if rule.Resource != "" && (matchResource(rule.Resource, id) || matchResource(rule.Resource, unqualified)) {
+			return true
+		}
+
+		if rule.Tag != "" && len(Intersect([]string{rule.Tag}, resourceTags)) > 0 {
+			return true
+		}
 	}
 
-	return false, false
+	return false
 }
 
-// CheckTagsWithTags evaluates a Datadog/AWS tag matcher.
-func (af AwsFilter) CheckTagsWithTags(ddTags datadog.Tags, resourceTags mapper.Tags) bool {
-	if af.AwsTagKey == "" || af.DdTagKey == "" {
-		return true
+func matchResource(pattern, id string) bool {
+	matched, err := path.Match(pattern, id)
+	return err == nil && matched
+}
+
+// unqualifyID strips the account and region prefixes from the identifier qualified by the multi-target mapper.
+// cf. mapper.AwsMultiTargetTagsMapper.
+func unqualifyID(id string, resourceTags mapper.Tags) string {
+	for _, key := range []string{"aws_account", "region"} {
+		for _, tag := range resourceTags {
+			k, v := splitTag(tag)
+			if k == key && v != "" && strings.HasPrefix(id, v+"/") {
+				id = strings.TrimPrefix(id, v+"/")
+				break
+			}
+		}
+	}
+
+	return id
+}
+
+// CheckRequired evaluates whether the metric must be monitored for the resource.
+func (af AwsFilter) CheckRequired(metric string, resourceTags mapper.Tags) bool {
+	for _, rm := range af.Required {
+		if rm.Scope != nil && !rm.Scope.Eval(resourceTags) {
+			continue
+		}
+
+		for _, m := range rm.Metrics {
+			if strings.EqualFold(m, metric) {
+				return true
+			}
+		}
 	}
 
+	return false
+}
+
+func checkTagKeyPair(pair config.TagKeyPair, ddTags datadog.Tags, resourceTags mapper.Tags) bool {
 	for _, dt := range ddTags {
-		dparts := strings.Split(dt, ":")
-		dk, dv := dparts[0], dparts[1]
-		if dk != af.DdTagKey {
+		dk, dv := splitTag(dt)
+		if dk != pair.DdTagKey {
 			continue
 		}
 
 		for _, at := range resourceTags {
-			aparts := strings.Split(at, ":")
-			ak, av := aparts[0], aparts[1]
-			if ak != af.AwsTagKey {
+			ak, av := splitTag(at)
+			if ak != pair.AwsTagKey {
 				continue
 			}
 
-			if dv == av {
+			if dv == mapTagValue(pair, av) {
 				return true
 			}
 		}
@@ -72,3 +140,26 @@ func (af AwsFilter) CheckTagsWithTags(ddTags datadog.Tags, resourceTags mapper.T
 
 	return false
 }
+
+// mapTagValue translates AWS tag value into Datadog tag value along with the pair's value mapping.
+// The value is returned as it is when no mapping is found.
+func mapTagValue(pair config.TagKeyPair, awsValue string) string {
+	for k, v := range pair.Values {
+		if strings.EqualFold(k, awsValue) {
+			return strings.ToLower(v)
+		}
+	}
+
+	return awsValue
+}
+
+// splitTag splits a tag into its key and value.
+// A tag value could contain ':', e.g. `statemachinearn:arn:aws:states:...`.
+func splitTag(tag string) (key, value string) {
+	parts := strings.SplitN(tag, ":", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/terakoya76/modd/config"
 	"github.com/terakoya76/modd/datadog"
 	"github.com/terakoya76/modd/filter"
 	"github.com/terakoya76/modd/mapper"
@@ -74,15 +75,40 @@ func Test_CheckScopeWithTags(t *testing.T) {
 			included: false,
 			excluded: true,
 		},
+		{
+			name:     "when scope has wildcard value",
+			scope:    []string{"env:prod-*"},
+			tags:     []string{"env:prod-tokyo"},
+			included: true,
+			excluded: false,
+		},
+		{
+			name:     "when scope has OR grouping",
+			scope:    []string{"env:prod", "(service:api OR service:web)"},
+			tags:     []string{"env:prod", "service:web"},
+			included: true,
+			excluded: false,
+		},
+		{
+			name:     "when scope has NOT IN list matching tags",
+			scope:    []string{"env NOT IN (dev, stg)"},
+			tags:     []string{"env:stg"},
+			included: false,
+			excluded: true,
+		},
+		{
+			name:     "when scope has IN list not matching tags",
+			scope:    []string{"env IN (dev, stg)"},
+			tags:     []string{"env:prod"},
+			included: false,
+			excluded: false,
+		},
 	}
 
 	for _, c := range cases {
-		af := filter.AwsFilter{
-			AwsTagKey: "",
-			DdTagKey:  "",
-		}
+		af := filter.AwsFilter{}
 
-		included, excluded := af.CheckScopeWithTags(c.scope, c.tags)
+		included, excluded := af.CheckScopeWithTags(datadog.ParseScopes(c.scope), c.tags)
 		if !assert.Equal(t, c.included, included) {
 			t.Errorf("case: %s is failed, expected: %t, actual: %t\n", c.name, c.included, included)
 		}
@@ -102,21 +128,15 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		expected bool
 	}{
 		{
-			name: "when filter holds no metadata",
-			filter: filter.AwsFilter{
-				AwsTagKey: "",
-				DdTagKey:  "",
-			},
+			name:     "when filter holds no metadata",
+			filter:   filter.AwsFilter{},
 			ddTags:   []string{},
 			awsTags:  []string{},
 			expected: true,
 		},
 		{
-			name: "when filter holds no metadata even if tags exist",
-			filter: filter.AwsFilter{
-				AwsTagKey: "",
-				DdTagKey:  "",
-			},
+			name:     "when filter holds no metadata even if tags exist",
+			filter:   filter.AwsFilter{},
 			ddTags:   []string{"a:b"},
 			awsTags:  []string{"c:d"},
 			expected: true,
@@ -124,8 +144,9 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		{
 			name: "when filter holds only AWS metadata",
 			filter: filter.AwsFilter{
-				AwsTagKey: "a",
-				DdTagKey:  "",
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: ""},
+				},
 			},
 			ddTags:   []string{},
 			awsTags:  []string{},
@@ -134,8 +155,9 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		{
 			name: "when filter holds only Datadog metadata",
 			filter: filter.AwsFilter{
-				AwsTagKey: "",
-				DdTagKey:  "a",
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "", DdTagKey: "a"},
+				},
 			},
 			ddTags:   []string{},
 			awsTags:  []string{},
@@ -144,8 +166,9 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		{
 			name: "when filter holds same key and tag's values are matched",
 			filter: filter.AwsFilter{
-				AwsTagKey: "a",
-				DdTagKey:  "a",
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+				},
 			},
 			ddTags:   []string{"a:b"},
 			awsTags:  []string{"a:b"},
@@ -154,8 +177,9 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		{
 			name: "when filter holds same key and tag's values are not matched",
 			filter: filter.AwsFilter{
-				AwsTagKey: "a",
-				DdTagKey:  "a",
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+				},
 			},
 			ddTags:   []string{"a:b"},
 			awsTags:  []string{"a:c"},
@@ -164,8 +188,9 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		{
 			name: "when filter holds different key and tag's values are matched",
 			filter: filter.AwsFilter{
-				AwsTagKey: "z",
-				DdTagKey:  "a",
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "z", DdTagKey: "a"},
+				},
 			},
 			ddTags:   []string{"a:b"},
 			awsTags:  []string{"z:b"},
@@ -174,8 +199,9 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		{
 			name: "when filter holds different key and tag's values are not matched",
 			filter: filter.AwsFilter{
-				AwsTagKey: "z",
-				DdTagKey:  "a",
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "z", DdTagKey: "a"},
+				},
 			},
 			ddTags:   []string{"a:b"},
 			awsTags:  []string{"z:c"},
@@ -184,8 +210,9 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		{
 			name: "when metadata and AWS tag are mismatched",
 			filter: filter.AwsFilter{
-				AwsTagKey: "a",
-				DdTagKey:  "a",
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+				},
 			},
 			ddTags:   []string{"a:b"},
 			awsTags:  []string{"z:b"},
@@ -194,13 +221,120 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		{
 			name: "when metadata and Datadog tag are mismatched",
 			filter: filter.AwsFilter{
-				AwsTagKey: "a",
-				DdTagKey:  "a",
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+				},
 			},
 			ddTags:   []string{"z:b"},
 			awsTags:  []string{"a:b"},
 			expected: false,
 		},
+		{
+			name: "when filter holds multiple keys and all of them are matched",
+			filter: filter.AwsFilter{
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+					{AwsTagKey: "z", DdTagKey: "c"},
+				},
+			},
+			ddTags:   []string{"a:b", "c:d"},
+			awsTags:  []string{"a:b", "z:d"},
+			expected: true,
+		},
+		{
+			name: "when filter holds multiple keys and one of them is not matched",
+			filter: filter.AwsFilter{
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+					{AwsTagKey: "z", DdTagKey: "c"},
+				},
+			},
+			ddTags:   []string{"a:b", "c:d"},
+			awsTags:  []string{"a:b", "z:e"},
+			expected: false,
+		},
+		{
+			name: "when tag's values contain colon",
+			filter: filter.AwsFilter{
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+				},
+			},
+			ddTags:   []string{"a:b:c"},
+			awsTags:  []string{"a:b:c"},
+			expected: true,
+		},
+		{
+			name: "when filter holds multiple keys matched with any and one of them is matched",
+			filter: filter.AwsFilter{
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+					{AwsTagKey: "z", DdTagKey: "c"},
+				},
+				TagMatch: config.TagMatchAny,
+			},
+			ddTags:   []string{"a:b", "c:d"},
+			awsTags:  []string{"a:b", "z:e"},
+			expected: true,
+		},
+		{
+			name: "when filter holds multiple keys matched with any and none of them is matched",
+			filter: filter.AwsFilter{
+				TagKeys: []config.TagKeyPair{
+					{AwsTagKey: "a", DdTagKey: "a"},
+					{AwsTagKey: "z", DdTagKey: "c"},
+				},
+				TagMatch: config.TagMatchAny,
+			},
+			ddTags:   []string{"a:b", "c:d"},
+			awsTags:  []string{"a:x", "z:e"},
+			expected: false,
+		},
+		{
+			name: "when AWS tag value is mapped into Datadog tag value",
+			filter: filter.AwsFilter{
+				TagKeys: []config.TagKeyPair{
+					{
+						AwsTagKey: "engine",
+						DdTagKey:  "dbengine",
+						Values:    map[string]string{"aurora-postgresql": "postgres"},
+					},
+				},
+			},
+			ddTags:   []string{"dbengine:postgres"},
+			awsTags:  []string{"engine:aurora-postgresql"},
+			expected: true,
+		},
+		{
+			name: "when mapped AWS tag value is not matched",
+			filter: filter.AwsFilter{
+				TagKeys: []config.TagKeyPair{
+					{
+						AwsTagKey: "engine",
+						DdTagKey:  "dbengine",
+						Values:    map[string]string{"aurora-mysql": "mysql"},
+					},
+				},
+			},
+			ddTags:   []string{"dbengine:postgres"},
+			awsTags:  []string{"engine:aurora-mysql"},
+			expected: false,
+		},
+		{
+			name: "when AWS tag value is not in value mapping",
+			filter: filter.AwsFilter{
+				TagKeys: []config.TagKeyPair{
+					{
+						AwsTagKey: "engine",
+						DdTagKey:  "dbengine",
+						Values:    map[string]string{"aurora-mysql": "mysql"},
+					},
+				},
+			},
+			ddTags:   []string{"dbengine:postgres"},
+			awsTags:  []string{"engine:postgres"},
+			expected: true,
+		},
 	}
 
 	for _, c := range cases {
@@ -210,3 +344,162 @@ func Test_CheckTagsWithTags_Aws(t *testing.T) {
 		}
 	}
 }
+
+func Test_CheckIgnored_Aws(t *testing.T) {
+	cases := []struct {
+		name     string
+		filter   filter.Filter
+		id       string
+		awsTags  mapper.Tags
+		expected bool
+	}{
+		{
+			name:     "when filter holds no ignore rule",
+			filter:   filter.AwsFilter{},
+			id:       "sandbox-db",
+			awsTags:  []string{"env:sandbox"},
+			expected: false,
+		},
+		{
+			name: "when resource pattern is matched",
+			filter: filter.AwsFilter{
+				Ignore: []config.IgnoreRule{
+					{Resource: "sandbox-*"},
+				},
+			},
+			id:       "sandbox-db",
+			awsTags:  []string{},
+			expected: true,
+		},
+		{
+			name: "when resource pattern is not matched",
+			filter: filter.AwsFilter{
+				Ignore: []config.IgnoreRule{
+					{Resource: "sandbox-*"},
+				},
+			},
+			id:       "production-db",
+			awsTags:  []string{},
+			expected: false,
+		},
+		{
+			name: "when resource pattern is matched with qualified identifier",
+			filter: filter.AwsFilter{
+				Ignore: []config.IgnoreRule{
+					{Resource: "sandbox-*"},
+				},
+			},
+			id:       "123456789012/us-east-1/sandbox-db",
+			awsTags:  []string{"aws_account:123456789012", "region:us-east-1"},
+			expected: true,
+		},
+		{
+			name: "when qualified resource pattern is matched",
+			filter: filter.AwsFilter{
+				Ignore: []config.IgnoreRule{
+					{Resource: "*/us-east-1/sandbox-*"},
+				},
+			},
+			id:       "123456789012/us-east-1/sandbox-db",
+			awsTags:  []string{"aws_account:123456789012", "region:us-east-1"},
+			expected: true,
+		},
+		{
+			name: "when qualified resource pattern is not matched",
+			filter: filter.AwsFilter{
+				Ignore: []config.IgnoreRule{
+					{Resource: "*/us-east-1/sandbox-*"},
+				},
+			},
+			id:       "123456789012/us-west-2/sandbox-db",
+			awsTags:  []string{"aws_account:123456789012", "region:us-west-2"},
+			expected: false,
+		},
+		{
+			name: "when tag is matched",
+			filter: filter.AwsFilter{
+				Ignore: []config.IgnoreRule{
+					{Tag: "env:sandbox"},
+				},
+			},
+			id:       "db",
+			awsTags:  []string{"env:sandbox"},
+			expected: true,
+		},
+		{
+			name: "when tag is not matched",
+			filter: filter.AwsFilter{
+				Ignore: []config.IgnoreRule{
+					{Tag: "env:sandbox"},
+				},
+			},
+			id:       "db",
+			awsTags:  []string{"env:production"},
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		actual := c.filter.CheckIgnored(c.id, c.awsTags)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %t, actual: %t\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_CheckRequired_Aws(t *testing.T) {
+	ic := config.IntegrationConfig{
+		RequiredMetrics: []config.RequiredMetricsRule{
+			{Metrics: []string{"aws.rds.cpuutilization", "aws.rds.free_storage_space"}, Scope: "env:production"},
+			{Metrics: []string{"aws.rds.database_connections"}},
+		},
+	}
+	f, err := filter.BuildFilter(datadog.AwsRds, ic)
+	if err != nil {
+		t.Fatalf("failed to build filter: %+v\n", err)
+	}
+
+	cases := []struct {
+		name     string
+		metric   string
+		awsTags  mapper.Tags
+		expected bool
+	}{
+		{
+			name:     "when resource is matched with scope",
+			metric:   "aws.rds.cpuutilization",
+			awsTags:  []string{"env:production"},
+			expected: true,
+		},
+		{
+			name:     "when resource is not matched with scope",
+			metric:   "aws.rds.cpuutilization",
+			awsTags:  []string{"env:stg"},
+			expected: false,
+		},
+		{
+			name:     "when rule has no scope",
+			metric:   "aws.rds.database_connections",
+			awsTags:  []string{},
+			expected: true,
+		},
+		{
+			name:     "when metric is not required",
+			metric:   "aws.rds.replica_lag",
+			awsTags:  []string{"env:production"},
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		actual := f.CheckRequired(c.metric, c.awsTags)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %t, actual: %t\n", c.name, c.expected, actual)
+		}
+	}
+
+	_, err = filter.BuildFilter(datadog.AwsRds, config.IntegrationConfig{
+		RequiredMetrics: []config.RequiredMetricsRule{{Metrics: []string{"aws.rds.cpuutilization"}, Scope: "env IN (prod"}},
+	})
+	assert.EqualError(t, err, `invalid scope of required metrics "env IN (prod": missing closing parenthesis of env IN`)
+}
@@ -3,158 +3,50 @@ package filter
 import (
 	"fmt"
 
-	"github.com/kelseyhightower/envconfig"
-
+	"github.com/terakoya76/modd/config"
 	"github.com/terakoya76/modd/datadog"
 	"github.com/terakoya76/modd/mapper"
 )
 
 // Filter is an interface to filter AWS resources which should be monitored.
 type Filter interface {
-	CheckScopeWithTags(scope datadog.Scope, tags mapper.Tags) (included bool, excluded bool)
+	CheckScopeWithTags(scope datadog.AndExpr, tags mapper.Tags) (included bool, excluded bool)
 	CheckTagsWithTags(ddTags datadog.Tags, resourceTags mapper.Tags) bool
+	CheckIgnored(id string, resourceTags mapper.Tags) bool
+	CheckRequired(metric string, resourceTags mapper.Tags) bool
 }
 
-// BuildFilter build the proper Filter implementation.
-//
-//nolint:funlen,gocyclo
-func BuildFilter(it datadog.IntegrationTarget) (Filter, error) {
-	envPrefix := string(it)
-
-	switch it {
-	case datadog.AwsAPIGateway:
-		var c AwsAPIGatewayConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsAutoScalingGroup:
-		var c AwsAutoScalingGroupConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsClb:
-		var c AwsClbConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsDynamoDB:
-		var c AwsDynamoDBConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsElastiCache:
-		var c AwsElastiCacheConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsElb:
-		var c AwsElbConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsFirehose:
-		var c AwsFirehoseConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsKinesis:
-		var c AwsKinesisConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsOpenSearchService:
-		var c AwsOpenSeardhServiceConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsRds:
-		var c AwsRdsConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsSns:
-		var c AwsSnsConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
-
-	case datadog.AwsStepFunction:
-		var c AwsStepFunctionConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
-
-		f := AwsFilter(c)
-		return f, nil
+// RequiredMetrics represents metrics which must be monitored for the resources matched with Scope.
+// A nil Scope matches every resource.
+type RequiredMetrics struct {
+	Metrics []string
+	Scope   datadog.ScopeExpr
+}
 
-	case datadog.AwsSqs:
-		var c AwsSqsConfig
-		err := envconfig.Process(envPrefix, &c)
-		if err != nil {
-			return nil, err
-		}
+// BuildFilter build the proper Filter implementation.
+func BuildFilter(it datadog.IntegrationTarget, ic config.IntegrationConfig) (Filter, error) {
+	if !datadog.IsSupportedIntegrationTarget(it) {
+		return nil, fmt.Errorf("unsupported IntegrationTarget")
+	}
 
-		f := AwsFilter(c)
-		return f, nil
+	required := make([]RequiredMetrics, 0, len(ic.RequiredMetrics))
+	for _, rule := range ic.RequiredMetrics {
+		rm := RequiredMetrics{Metrics: rule.Metrics}
+		if rule.Scope != "" {
+			expr, err := datadog.ParseScope(rule.Scope)
+			if err != nil {
+				return nil, fmt.Errorf("invalid scope of required metrics %q: %w", rule.Scope, err)
+			}
+			rm.Scope = expr
+		}
+		required = append(required, rm)
+	}
 
-	case datadog.UnknownIntegration:
-		return nil, fmt.Errorf("unsupported IntegrationTarget")
-	default:
-		return nil, fmt.Errorf("unsupported IntegrationTarget")
+	f := AwsFilter{
+		TagKeys:  ic.TagKeys,
+		TagMatch: ic.TagMatch,
+		Ignore:   ic.Ignore,
+		Required: required,
 	}
+	return f, nil
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/terakoya76/modd/history"
+	"github.com/terakoya76/modd/report"
+)
+
+// historyViews represents the views of the scan history.
+var historyViews = []string{"trends", "gaps"}
+
+// runHistory reports coverage trends or unmonitored periods from the scan history.
+func runHistory(args []string, stdout, stderr io.Writer) int {
+	var cf commonFlags
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	cf.register(fs)
+	cf.registerFormat(fs)
+	cf.registerHistory(fs)
+	since := fs.Duration("since", 0, "report the scans within the duration, e.g. 720h (every scan by default)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: modd history [flags] <trends|gaps>\n\n")
+		fs.PrintDefaults()
+	}
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	if fs.NArg() != 1 || !containsString(historyViews, fs.Arg(0)) {
+		fs.Usage()
+		return 1
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	if cfg.History == "" {
+		fmt.Fprintf(stderr, "history is not configured, set history of the configuration or -history\n")
+		return 1
+	}
+
+	store, err := history.Open(cfg.History)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+	defer store.Close()
+
+	var from time.Time
+	if *since > 0 {
+		from = time.Now().Add(-*since)
+	}
+
+	records, err := store.List(from)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	switch fs.Arg(0) {
+	case "trends":
+		err = history.WriteTrends(stdout, history.Trends(records), cfg.Output.Format, cfg.Output.Pretty)
+	case "gaps":
+		err = history.WriteGaps(stdout, history.Gaps(records), cfg.Output.Format, cfg.Output.Pretty)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to write history: %v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	return 0
+}
+
+// recordHistory records the scan result into the configured history, and returns the previous scan result if any.
+func recordHistory(path string, scannedAt time.Time, scan report.Scan) (*report.Scan, error) {
+	store, err := history.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer store.Close()
+
+	latest, found, err := store.Latest()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := store.Save(scannedAt, scan); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return &latest.Scan, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,178 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/report"
+)
+
+// TrendPoint represents the monitoring status of an integration at a scan.
+// Unmonitored is the number of unmonitored pairs of metric and resource.
+type TrendPoint struct {
+	ScannedAt   time.Time
+	Integration string
+	Resources   int
+	Unmonitored int
+	Coverage    float64
+}
+
+// Gap represents a period in which a resource is unmonitored for a metric.
+// Remediated is the first scan in which the resource is monitored or deleted, and nil while the gap is open.
+// A resource unmonitored again after the remediation opens another Gap.
+type Gap struct {
+	Integration      datadog.IntegrationTarget
+	Metric           string
+	Resource         string
+	FirstUnmonitored time.Time
+	LastUnmonitored  time.Time
+	Remediated       *time.Time
+	TimeToRemediate  time.Duration
+}
+
+// RemediationSummary represents the gaps of an integration.
+// MeanTimeToRemediate is averaged over the remediated gaps.
+type RemediationSummary struct {
+	Integration         datadog.IntegrationTarget
+	Open                int
+	Remediated          int
+	MeanTimeToRemediate time.Duration
+}
+
+// Trends returns the coverage of each integration per scan in chronological order.
+func Trends(records []Record) []TrendPoint {
+	points := make([]TrendPoint, 0, len(records))
+	for _, r := range records {
+		matrix := report.BuildCoverageMatrix(r.Scan)
+		for _, ic := range matrix.Integrations {
+			unmonitored := 0
+			for _, rc := range ic.Resources {
+				for _, status := range rc.Metrics {
+					if status == report.StatusUnmonitored {
+						unmonitored++
+					}
+				}
+			}
+
+			points = append(points, TrendPoint{
+				ScannedAt:   r.ScannedAt,
+				Integration: ic.Integration,
+				Resources:   len(ic.Resources),
+				Unmonitored: unmonitored,
+				Coverage:    ic.Coverage,
+			})
+		}
+	}
+
+	return points
+}
+
+// Gaps returns the periods in which each resource is unmonitored for each metric from the records in chronological order.
+// A gap is kept open while its metric is not evaluated, e.g. by scans of other integrations or failed evaluations,
+// and while the resource is excluded or suppressed by the baseline.
+func Gaps(records []Record) []Gap {
+	gaps := make([]Gap, 0)
+	open := make(map[gapKey]int)
+
+	for _, r := range records {
+		for _, m := range r.Scan.Metrics {
+			unmonitored := make(map[string]struct{}, len(m.Unmonitored))
+			for _, id := range m.Unmonitored {
+				unmonitored[id] = struct{}{}
+
+				k := gapKey{metric: m.Metric, resource: id}
+				if i, ok := open[k]; ok {
+					gaps[i].LastUnmonitored = r.ScannedAt
+					continue
+				}
+
+				open[k] = len(gaps)
+				gaps = append(gaps, Gap{
+					Integration:      m.Integration,
+					Metric:           m.Metric,
+					Resource:         id,
+					FirstUnmonitored: r.ScannedAt,
+					LastUnmonitored:  r.ScannedAt,
+				})
+			}
+
+			resources := make(map[string]struct{}, len(m.Resources))
+			for _, id := range m.Resources {
+				resources[id] = struct{}{}
+			}
+
+			for k, i := range open {
+				if k.metric != m.Metric {
+					continue
+				}
+				if _, ok := unmonitored[k.resource]; ok {
+					continue
+				}
+
+				// excluded or suppressed resources are not remediated
+				_, monitored := m.Monitored[k.resource]
+				_, exists := resources[k.resource]
+				if !monitored && exists {
+					continue
+				}
+
+				remediated := r.ScannedAt
+				gaps[i].Remediated = &remediated
+				gaps[i].TimeToRemediate = remediated.Sub(gaps[i].FirstUnmonitored)
+				delete(open, k)
+			}
+		}
+	}
+
+	sort.SliceStable(gaps, func(i, j int) bool {
+		if gaps[i].Integration != gaps[j].Integration {
+			return gaps[i].Integration < gaps[j].Integration
+		}
+		if gaps[i].Metric != gaps[j].Metric {
+			return gaps[i].Metric < gaps[j].Metric
+		}
+		return gaps[i].Resource < gaps[j].Resource
+	})
+
+	return gaps
+}
+
+// SummarizeRemediation returns the number of open/remediated gaps and the mean time to remediate per integration.
+func SummarizeRemediation(gaps []Gap) []RemediationSummary {
+	byIntegration := make(map[datadog.IntegrationTarget]*RemediationSummary)
+	totals := make(map[datadog.IntegrationTarget]time.Duration)
+	for _, g := range gaps {
+		s, ok := byIntegration[g.Integration]
+		if !ok {
+			s = &RemediationSummary{Integration: g.Integration}
+			byIntegration[g.Integration] = s
+		}
+
+		if g.Remediated == nil {
+			s.Open++
+			continue
+		}
+		s.Remediated++
+		totals[g.Integration] += g.TimeToRemediate
+	}
+
+	summaries := make([]RemediationSummary, 0, len(byIntegration))
+	for it, s := range byIntegration {
+		if s.Remediated > 0 {
+			s.MeanTimeToRemediate = totals[it] / time.Duration(s.Remediated)
+		}
+		summaries = append(summaries, *s)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Integration < summaries[j].Integration
+	})
+
+	return summaries
+}
+
+type gapKey struct {
+	metric   string
+	resource string
+}
@@ -0,0 +1,259 @@
+package history_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/history"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_Trends(t *testing.T) {
+	day1 := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	cases := []struct {
+		name     string
+		records  []history.Record
+		expected []history.TrendPoint
+	}{
+		{
+			name: "when the metric is not evaluated in some scans",
+			records: []history.Record{
+				{
+					ScannedAt: day1,
+					Scan: report.Scan{
+						Metrics: []report.MetricResult{
+							{
+								Metric:      "aws.rds.cpuutilization",
+								Integration: datadog.AwsRds,
+								Result: evaluator.Result{
+									Resources:   []string{"db-1", "db-2", "db-3"},
+									Unmonitored: []string{"db-1", "db-2"},
+									Excluded:    []string{},
+									Monitored:   map[string][]datadog.MonitorRef{"db-3": {{ID: 1}}},
+								},
+							},
+						},
+					},
+				},
+				{ScannedAt: day2, Scan: report.Scan{}},
+				{
+					ScannedAt: day3,
+					Scan: report.Scan{
+						Metrics: []report.MetricResult{
+							{
+								Metric:      "aws.rds.cpuutilization",
+								Integration: datadog.AwsRds,
+								Result: evaluator.Result{
+									Resources:   []string{"db-1", "db-2", "db-3"},
+									Unmonitored: []string{"db-1"},
+									Excluded:    []string{},
+									Monitored:   map[string][]datadog.MonitorRef{"db-2": {{ID: 1}}, "db-3": {{ID: 1}}},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []history.TrendPoint{
+				{ScannedAt: day1, Integration: "aws_rds", Resources: 3, Unmonitored: 2, Coverage: 100.0 / 3},
+				{ScannedAt: day3, Integration: "aws_rds", Resources: 3, Unmonitored: 1, Coverage: 200.0 / 3},
+			},
+		},
+		{
+			name:     "when no scan is recorded",
+			records:  []history.Record{},
+			expected: []history.TrendPoint{},
+		},
+	}
+
+	for _, c := range cases {
+		actual := history.Trends(c.records)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_Gaps(t *testing.T) {
+	day1 := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+	day4 := day1.AddDate(0, 0, 3)
+
+	cases := []struct {
+		name     string
+		records  []history.Record
+		expected []history.Gap
+		summary  []history.RemediationSummary
+	}{
+		{
+			name: "when a resource becomes unmonitored again",
+			records: []history.Record{
+				{
+					ScannedAt: day1,
+					Scan: report.Scan{
+						Metrics: []report.MetricResult{
+							{
+								Metric:      "aws.rds.cpuutilization",
+								Integration: datadog.AwsRds,
+								Result: evaluator.Result{
+									Resources:   []string{"db-1", "db-2", "db-3"},
+									Unmonitored: []string{"db-1", "db-2"},
+									Excluded:    []string{},
+									Monitored:   map[string][]datadog.MonitorRef{"db-3": {{ID: 1}}},
+								},
+							},
+						},
+					},
+				},
+				{
+					ScannedAt: day2,
+					Scan: report.Scan{
+						Metrics: []report.MetricResult{
+							{
+								Metric:      "aws.rds.cpuutilization",
+								Integration: datadog.AwsRds,
+								Result: evaluator.Result{
+									Resources:   []string{"db-1", "db-2", "db-3"},
+									Unmonitored: []string{"db-2"},
+									Excluded:    []string{},
+									Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}, "db-3": {{ID: 1}}},
+								},
+							},
+						},
+					},
+				},
+				// the metric is not evaluated
+				{ScannedAt: day3, Scan: report.Scan{}},
+				{
+					ScannedAt: day4,
+					Scan: report.Scan{
+						Metrics: []report.MetricResult{
+							{
+								Metric:      "aws.rds.cpuutilization",
+								Integration: datadog.AwsRds,
+								Result: evaluator.Result{
+									Resources:   []string{"db-1", "db-2", "db-3"},
+									Unmonitored: []string{"db-1"},
+									Excluded:    []string{},
+									Monitored:   map[string][]datadog.MonitorRef{"db-2": {{ID: 1}}, "db-3": {{ID: 1}}},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []history.Gap{
+				{
+					Integration:      datadog.AwsRds,
+					Metric:           "aws.rds.cpuutilization",
+					Resource:         "db-1",
+					FirstUnmonitored: day1,
+					LastUnmonitored:  day1,
+					Remediated:       &day2,
+					TimeToRemediate:  24 * time.Hour,
+				},
+				{
+					Integration:      datadog.AwsRds,
+					Metric:           "aws.rds.cpuutilization",
+					Resource:         "db-1",
+					FirstUnmonitored: day4,
+					LastUnmonitored:  day4,
+				},
+				{
+					Integration:      datadog.AwsRds,
+					Metric:           "aws.rds.cpuutilization",
+					Resource:         "db-2",
+					FirstUnmonitored: day1,
+					LastUnmonitored:  day2,
+					Remediated:       &day4,
+					TimeToRemediate:  72 * time.Hour,
+				},
+			},
+			summary: []history.RemediationSummary{
+				{Integration: datadog.AwsRds, Open: 1, Remediated: 2, MeanTimeToRemediate: 48 * time.Hour},
+			},
+		},
+		{
+			name: "when a resource is excluded or deleted",
+			records: []history.Record{
+				{
+					ScannedAt: day1,
+					Scan: report.Scan{
+						Metrics: []report.MetricResult{
+							{
+								Metric:      "aws.rds.cpuutilization",
+								Integration: datadog.AwsRds,
+								Result: evaluator.Result{
+									Resources:   []string{"db-1", "db-2"},
+									Unmonitored: []string{"db-1", "db-2"},
+									Excluded:    []string{},
+									Monitored:   map[string][]datadog.MonitorRef{},
+								},
+							},
+						},
+					},
+				},
+				{
+					ScannedAt: day2,
+					Scan: report.Scan{
+						Metrics: []report.MetricResult{
+							{
+								Metric:      "aws.rds.cpuutilization",
+								Integration: datadog.AwsRds,
+								Result: evaluator.Result{
+									Resources:   []string{"db-1"},
+									Unmonitored: []string{},
+									Excluded:    []string{"db-1"},
+									Monitored:   map[string][]datadog.MonitorRef{},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []history.Gap{
+				{
+					Integration:      datadog.AwsRds,
+					Metric:           "aws.rds.cpuutilization",
+					Resource:         "db-1",
+					FirstUnmonitored: day1,
+					LastUnmonitored:  day1,
+				},
+				{
+					Integration:      datadog.AwsRds,
+					Metric:           "aws.rds.cpuutilization",
+					Resource:         "db-2",
+					FirstUnmonitored: day1,
+					LastUnmonitored:  day1,
+					Remediated:       &day2,
+					TimeToRemediate:  24 * time.Hour,
+				},
+			},
+			summary: []history.RemediationSummary{
+				{Integration: datadog.AwsRds, Open: 1, Remediated: 1, MeanTimeToRemediate: 24 * time.Hour},
+			},
+		},
+		{
+			name:     "when no scan is recorded",
+			records:  []history.Record{},
+			expected: []history.Gap{},
+			summary:  []history.RemediationSummary{},
+		},
+	}
+
+	for _, c := range cases {
+		gaps := history.Gaps(c.records)
+		summary := history.SummarizeRemediation(gaps)
+		if !assert.Equal(t, c.expected, gaps) || !assert.Equal(t, c.summary, summary) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, gaps)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/terakoya76/modd/report"
+)
+
+// scansBucket holds the scan records keyed by the scanned time.
+var scansBucket = []byte("scans")
+
+// Store records scan results into an embedded database file.
+type Store struct {
+	db *bolt.DB
+}
+
+// Record represents a scan result recorded at ScannedAt.
+type Record struct {
+	ScannedAt time.Time
+	Scan      report.Scan
+}
+
+// Open opens the database file, and creates it when it does not exist.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, createErr := tx.CreateBucketIfNotExists(scansBucket)
+		return createErr
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize history %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database file.
+func (s *Store) Close() error {
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// Save records the scan result scanned at the time.
+// A record scanned at the same time is overwritten.
+func (s *Store) Save(scannedAt time.Time, scan report.Scan) error {
+	v, err := json.Marshal(Record{ScannedAt: scannedAt.UTC(), Scan: scan})
+	if err != nil {
+		return fmt.Errorf("failed to encode scan record: %w", err)
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(scansBucket).Put(timeKey(scannedAt), v)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save scan record: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the records scanned since the time in chronological order.
+// Every record is returned when since is zero.
+func (s *Store) List(since time.Time) ([]Record, error) {
+	records := make([]Record, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(scansBucket).Cursor()
+
+		k, v := c.First()
+		if !since.IsZero() {
+			k, v = c.Seek(timeKey(since))
+		}
+
+		for ; k != nil; k, v = c.Next() {
+			var r Record
+			if err := json.Unmarshal(v, &r); err != nil {
+				return fmt.Errorf("failed to decode scan record: %w", err)
+			}
+			records = append(records, r)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan records: %w", err)
+	}
+
+	return records, nil
+}
+
+// Latest returns the last record, and false when nothing is recorded.
+func (s *Store) Latest() (Record, bool, error) {
+	var r Record
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		_, v := tx.Bucket(scansBucket).Cursor().Last()
+		if v == nil {
+			return nil
+		}
+
+		found = true
+		return json.Unmarshal(v, &r)
+	})
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to get the latest scan record: %w", err)
+	}
+
+	return r, found, nil
+}
+
+// timeKey returns the key sorted in chronological order.
+func timeKey(t time.Time) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+
+	return k
+}
@@ -0,0 +1,125 @@
+package history_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/history"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_Store(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+	if !assert.Nil(t, err) {
+		t.Fatalf("failed to open history: %+v\n", err)
+	}
+	defer store.Close()
+
+	_, found, err := store.Latest()
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	day1 := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	// saved out of order
+	saved := []history.Record{
+		{
+			ScannedAt: day2,
+			Scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1, Name: "rds cpu"}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			ScannedAt: day1,
+			Scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2"},
+							Unmonitored: []string{"db-1", "db-2"},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{},
+						},
+					},
+				},
+			},
+		},
+		{
+			ScannedAt: day3,
+			Scan:      report.Scan{Metrics: []report.MetricResult{}},
+		},
+	}
+	for _, r := range saved {
+		if err = store.Save(r.ScannedAt, r.Scan); err != nil {
+			t.Fatalf("failed to save scan: %+v\n", err)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		since    time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "when since is zero",
+			since:    time.Time{},
+			expected: []time.Time{day1, day2, day3},
+		},
+		{
+			name:     "when since is in the middle",
+			since:    day1.Add(time.Hour),
+			expected: []time.Time{day2, day3},
+		},
+		{
+			name:     "when since is after every scan",
+			since:    day3.Add(time.Hour),
+			expected: []time.Time{},
+		},
+	}
+
+	for _, c := range cases {
+		records, listErr := store.List(c.since)
+		if !assert.Nil(t, listErr) {
+			t.Fatalf("case: %s is failed, err: %+v\n", c.name, listErr)
+		}
+
+		actual := make([]time.Time, 0, len(records))
+		for _, r := range records {
+			actual = append(actual, r.ScannedAt)
+		}
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+
+	records, err := store.List(time.Time{})
+	if !assert.Nil(t, err) {
+		t.Fatalf("failed to list scans: %+v\n", err)
+	}
+	assert.Equal(t, saved[1].Scan, records[0].Scan)
+
+	latest, found, err := store.Latest()
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, day3, latest.ScannedAt)
+}
@@ -0,0 +1,82 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/report"
+)
+
+// GapReport represents the gaps with their summary per integration.
+type GapReport struct {
+	Gaps    []Gap
+	Summary []RemediationSummary
+}
+
+// WriteTrends writes the trend points in the format.
+// Only json and table formats are supported.
+func WriteTrends(w io.Writer, points []TrendPoint, format string, pretty bool) error {
+	switch format {
+	case config.JSONFormat:
+		return report.WriteJSONValue(w, points, pretty)
+	case config.TableFormat:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SCANNED AT\tINTEGRATION\tRESOURCES\tUNMONITORED\tCOVERAGE")
+		for _, p := range points {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.1f%%\n", p.ScannedAt.Format(time.RFC3339), p.Integration, p.Resources, p.Unmonitored, p.Coverage)
+		}
+
+		return flush(tw)
+	default:
+		return fmt.Errorf("unsupported output format of history %q, must be one of json or table", format)
+	}
+}
+
+// WriteGaps writes the gaps and their summary in the format.
+// Only json and table formats are supported.
+func WriteGaps(w io.Writer, gaps []Gap, format string, pretty bool) error {
+	switch format {
+	case config.JSONFormat:
+		return report.WriteJSONValue(w, GapReport{Gaps: gaps, Summary: SummarizeRemediation(gaps)}, pretty)
+	case config.TableFormat:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "INTEGRATION\tMETRIC\tRESOURCE\tFIRST UNMONITORED\tREMEDIATED\tTIME TO REMEDIATE")
+		for _, g := range gaps {
+			remediated, ttr := "-", "-"
+			if g.Remediated != nil {
+				remediated = g.Remediated.Format(time.RFC3339)
+				ttr = g.TimeToRemediate.Round(time.Minute).String()
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", g.Integration, g.Metric, g.Resource, g.FirstUnmonitored.Format(time.RFC3339), remediated, ttr)
+		}
+		if err := flush(tw); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "INTEGRATION\tOPEN\tREMEDIATED\tMEAN TIME TO REMEDIATE")
+		for _, s := range SummarizeRemediation(gaps) {
+			mttr := "-"
+			if s.Remediated > 0 {
+				mttr = s.MeanTimeToRemediate.Round(time.Minute).String()
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", s.Integration, s.Open, s.Remediated, mttr)
+		}
+
+		return flush(tw)
+	default:
+		return fmt.Errorf("unsupported output format of history %q, must be one of json or table", format)
+	}
+}
+
+func flush(tw *tabwriter.Writer) error {
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
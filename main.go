@@ -1,121 +1,82 @@
 package main
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"sort"
-	"sync"
+)
 
-	"github.com/terakoya76/modd/datadog"
-	"github.com/terakoya76/modd/evaluator"
+var (
+	// Version is set on build.
+	Version = "dev"
+	// Revision is set on build.
+	Revision = "unknown"
 )
 
-type monitorStatus struct {
-	Name        string
-	Unmonitored []string
-}
+const usage = `Usage: modd <command> [flags]
 
-func main() {
-	ctx := datadog.GetDatadogContext()
+Commands:
+  scan               detect resources unmonitored by Datadog monitors (default)
+  explain            explain how a resource is evaluated against the monitors
+  serve              rescan periodically and serve the latest result over HTTP
+  diff               compare two saved scan results, or a saved one with a live scan
+  history            report coverage trends or unmonitored periods from the scan history
+  baseline           regenerate the baseline file accepting the current unmonitored resources
+  list-integrations  list supported integrations
+  version            print the version
 
-	ddClient := datadog.GetDatadogClient()
-	metadata, err := datadog.GetMetadata(ctx, ddClient)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "faield to get monitor metadata: %v\n", err)
-		os.Exit(1)
-	}
+Run 'modd <command> -h' for the flags of each command.
+`
 
-	monitors, err := datadog.ListMonitors(ctx, ddClient, metadata)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "faield to list monitors: %v\n", err)
-		os.Exit(1)
-	}
-
-	ddMonitorTagsMapping, err := datadog.GetMonitorTagsMapping(monitors)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to get monitor/tags mapping: %v\n", err)
-		os.Exit(1)
-	}
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
 
-	ddMonitorScopesMapping, err := datadog.GetMonitorScopesMapping(monitors)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to get monitor/scopes mapping: %v\n", err)
-		os.Exit(1)
+// run dispatches the subcommand and returns the exit code.
+// The scan command runs when no subcommand is given to keep `modd -config modd.yaml` working.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (len(args[0]) > 0 && args[0][0] == '-' && args[0] != "-h" && args[0] != "-help") {
+		return runScan(args, stdout, stderr)
 	}
 
-	monitorStatuses, unsupported, err := checkUnmonitored(ctx, ddMonitorScopesMapping, ddMonitorTagsMapping)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to check monitor status: %v\n", err)
-		os.Exit(1)
+	switch args[0] {
+	case "scan":
+		return runScan(args[1:], stdout, stderr)
+	case "explain":
+		return runExplain(args[1:], stdout, stderr)
+	case "serve":
+		return runServe(args[1:], stdout, stderr)
+	case "diff":
+		return runDiff(args[1:], stdout, stderr)
+	case "history":
+		return runHistory(args[1:], stdout, stderr)
+	case "baseline":
+		return runBaseline(args[1:], stdout, stderr)
+	case "list-integrations":
+		return runListIntegrations(args[1:], stdout, stderr)
+	case "version":
+		fmt.Fprintf(stdout, "modd %s (rev: %s)\n", Version, Revision)
+		return 0
+	case "-h", "-help", "help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 1
 	}
-
-	result := make(map[string]interface{})
-	result["Monitors"] = monitorStatuses
-	result["Unsupported"] = unsupported
-
-	j, _ := json.Marshal(result)
-	fmt.Fprintf(os.Stdout, "%s", j)
 }
 
-func checkUnmonitored(
-	ctx context.Context,
-	monitorScopesMapping datadog.MonitorScopesMapping,
-	monitorTagsMapping datadog.MonitorTagsMapping,
-) ([]monitorStatus, []string, error) {
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	unsupported := make([]string, 0)
-	monitorStatuses := make([]monitorStatus, 0)
-	for metric, scopes := range monitorScopesMapping {
-		ddTags := monitorTagsMapping[metric]
-
-		it := datadog.MetricToIntegrationTarget(metric)
-		if it == datadog.UnknownIntegration {
-			unsupported = append(unsupported, metric)
-			continue
+// parseFlags parses the flags, and returns the exit code when the command should not continue.
+func parseFlags(fs *flag.FlagSet, args []string, stderr io.Writer) (int, bool) {
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0, false
 		}
-
-		e, err := evaluator.BuildEvaluator(it)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get Evaluator object: %v", err)
-		}
-
-		wg.Add(1)
-		go func(metric string, scopes []datadog.Scope) {
-			defer wg.Done()
-
-			unmonitored, err := e.Evaluate(ctx, scopes, ddTags)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "failed to filter monitors: %v\n", err)
-				return
-			}
-
-			mu.Lock()
-			defer mu.Unlock()
-
-			ms := monitorStatus{
-				Name:        metric,
-				Unmonitored: unmonitored,
-			}
-
-			monitorStatuses = append(monitorStatuses, ms)
-		}(metric, scopes)
-	}
-
-	wg.Wait()
-
-	sort.Slice(monitorStatuses, func(i, j int) bool {
-		return monitorStatuses[i].Name < monitorStatuses[j].Name
-	})
-
-	for i := 0; i < len(monitorStatuses); i++ {
-		sort.Strings(monitorStatuses[i].Unmonitored)
+		return 1, false
 	}
 
-	sort.Strings(unsupported)
-
-	return monitorStatuses, unsupported, nil
+	return 0, true
 }
@@ -0,0 +1,267 @@
+package mapper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	goCache "github.com/patrickmn/go-cache"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// AwsAllRegions represents all the regions enabled for the account.
+	AwsAllRegions string = "all"
+
+	awsRegionsCacheKeyPrefix string = "aws_regions"
+)
+
+var (
+	regionsCache = goCache.New(goCache.NoExpiration, goCache.NoExpiration)
+
+	accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+)
+
+// AwsAccount represents an AWS account to be scanned via STS AssumeRole.
+// The zero value represents the account of the default credentials.
+type AwsAccount struct {
+	ID      string
+	RoleArn string
+}
+
+// AwsRegionClient is abstract interface of *ec2.Client to list regions.
+type AwsRegionClient interface {
+	DescribeRegions(
+		ctx context.Context,
+		params *ec2.DescribeRegionsInput,
+		optFns ...func(*ec2.Options),
+	) (*ec2.DescribeRegionsOutput, error)
+}
+
+// LoadAwsConfig returns AWS SDK config shared by every AWS client.
+func LoadAwsConfig(ctx context.Context, optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+	opts := make([]func(*config.LoadOptions) error, 0, len(optFns)+1)
+	opts = append(opts, config.WithRetryer(func() aws.Retryer {
+		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
+	}))
+	opts = append(opts, optFns...)
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("%w", err)
+	}
+
+	return cfg, nil
+}
+
+// GetAwsRegions returns the regions to be scanned.
+// When no region is specified, the region of the default config is used.
+func GetAwsRegions(ctx context.Context, regions []string) ([]string, error) {
+	cacheKey := fmt.Sprintf("%s:%s", awsRegionsCacheKeyPrefix, strings.Join(regions, ","))
+	if cv, found := regionsCache.Get(cacheKey); found {
+		return cv.([]string), nil
+	}
+
+	cfg, err := LoadAwsConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	resolved, err := ResolveAwsRegions(ctx, ec2.NewFromConfig(cfg), cfg.Region, regions)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	regionsCache.Set(cacheKey, resolved, goCache.NoExpiration)
+	return resolved, nil
+}
+
+// ResolveAwsRegions expands the specified regions into the list of region names.
+func ResolveAwsRegions(
+	ctx context.Context, client AwsRegionClient, defaultRegion string, regions []string,
+) ([]string, error) {
+	if len(regions) == 0 {
+		return []string{defaultRegion}, nil
+	}
+
+	resolved := make([]string, 0, len(regions))
+	for _, region := range regions {
+		if region != AwsAllRegions {
+			resolved = append(resolved, region)
+			continue
+		}
+
+		// https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/ec2#DescribeRegionsInput
+		// Only the regions enabled for the account are returned when AllRegions is false.
+		output, err := client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{AllRegions: aws.Bool(false)})
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		for _, r := range output.Regions {
+			resolved = append(resolved, *r.RegionName)
+		}
+	}
+
+	return makeUniq(resolved), nil
+}
+
+// GetAwsAccounts returns the accounts to be scanned from account IDs or role ARNs.
+// An account ID is expanded into the role ARN with the specified role name.
+// When no account is specified, only the account of the default credentials is scanned.
+func GetAwsAccounts(accounts []string, roleName string) ([]AwsAccount, error) {
+	if len(accounts) == 0 {
+		return []AwsAccount{{}}, nil
+	}
+
+	resolved := make([]AwsAccount, 0, len(accounts))
+	for _, account := range accounts {
+		if accountIDPattern.MatchString(account) {
+			if roleName == "" {
+				return nil, fmt.Errorf("role name is required to assume role in the account %s", account)
+			}
+
+			resolved = append(resolved, AwsAccount{
+				ID:      account,
+				RoleArn: fmt.Sprintf("arn:aws:iam::%s:role/%s", account, roleName),
+			})
+			continue
+		}
+
+		// cf. arn:aws:iam::123456789012:role/my-role
+		parts := strings.Split(account, ":")
+		if len(parts) != 6 || parts[0] != "arn" || parts[2] != "iam" || !accountIDPattern.MatchString(parts[4]) {
+			return nil, fmt.Errorf("invalid AWS account ID or role ARN: %s", account)
+		}
+
+		resolved = append(resolved, AwsAccount{
+			ID:      parts[4],
+			RoleArn: account,
+		})
+	}
+
+	return resolved, nil
+}
+
+// WithAssumeRole returns the config option to use the credentials of the assumed role.
+func WithAssumeRole(ctx context.Context, roleArn string) (func(*config.LoadOptions) error, error) {
+	cfg, err := LoadAwsConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleArn)
+	return config.WithCredentialsProvider(aws.NewCredentialsCache(provider)), nil
+}
+
+// AwsTarget represents a pair of AWS account and region to be scanned.
+type AwsTarget struct {
+	AccountID string
+	Region    string
+}
+
+// TargetTagsMapper holds a TagsMapper bound to the specific AwsTarget.
+type TargetTagsMapper struct {
+	Target     AwsTarget
+	TagsMapper TagsMapper
+}
+
+// AwsMultiTargetTagsMapper implements TagsMapper fanning out over accounts and regions.
+type AwsMultiTargetTagsMapper struct {
+	mappers []TargetTagsMapper
+}
+
+// BuildAwsMultiTargetTagsMapper builds AwsMultiTargetTagsMapper from args.
+func BuildAwsMultiTargetTagsMapper(mappers []TargetTagsMapper) AwsMultiTargetTagsMapper {
+	return AwsMultiTargetTagsMapper{
+		mappers: mappers,
+	}
+}
+
+// GetTagsMapping returns the latest tags mapping merged over accounts and regions.
+// Each resource is tagged with `aws_account:<id>` and `region:<name>` as Datadog AWS integration does.
+// When more than one account or region is scanned, its identifier is prefixed with them
+// to avoid conflicts between the same names, e.g. `123456789012/us-east-1/test-db-1`.
+func (tm AwsMultiTargetTagsMapper) GetTagsMapping(ctx context.Context) (map[string]Tags, error) {
+	var mu sync.Mutex
+	mapping := make(map[string]Tags)
+
+	accounts := make(map[string]struct{})
+	regions := make(map[string]struct{})
+	for _, m := range tm.mappers {
+		accounts[m.Target.AccountID] = struct{}{}
+		regions[m.Target.Region] = struct{}{}
+	}
+	qualifyAccount, qualifyRegion := len(accounts) > 1, len(regions) > 1
+
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, tgm := range tm.mappers {
+		tgm := tgm
+		eg.Go(func() error {
+			target := tgm.Target
+			m, err := tgm.TagsMapper.GetTagsMapping(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get tags mapping in %s/%s: %w", target.AccountID, target.Region, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for id, tags := range m {
+				targetTags := make(Tags, len(tags), len(tags)+2)
+				copy(targetTags, tags)
+				if target.AccountID != "" {
+					targetTags = append(targetTags, fmt.Sprintf("aws_account:%s", target.AccountID))
+				}
+				if target.Region != "" {
+					targetTags = append(targetTags, fmt.Sprintf("region:%s", target.Region))
+				}
+
+				if qualifyRegion {
+					id = fmt.Sprintf("%s/%s", target.Region, id)
+				}
+				if qualifyAccount {
+					id = fmt.Sprintf("%s/%s", target.AccountID, id)
+				}
+				mapping[id] = targetTags
+			}
+
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return mapping, nil
+}
+
+// awsIntegrationTag returns the tag which Datadog AWS integration adds to the metrics.
+// cf. https://docs.datadoghq.com/integrations/amazon_web_services/#tags
+func awsIntegrationTag(key, value string) string {
+	return fmt.Sprintf("%s:%s", key, strings.ToLower(value))
+}
+
+func makeUniq(arr []string) []string {
+	seen := make(map[string]struct{}, len(arr))
+	uniq := make([]string, 0, len(arr))
+	for _, elmt := range arr {
+		if _, ok := seen[elmt]; ok {
+			continue
+		}
+
+		seen[elmt] = struct{}{}
+		uniq = append(uniq, elmt)
+	}
+
+	return uniq
+}
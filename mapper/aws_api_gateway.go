@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/apigateway"
 	goCache "github.com/patrickmn/go-cache"
@@ -40,10 +39,8 @@ func BuildAwsAPIGatewayTagsMapper(cache *goCache.Cache, client AwsAPIGatewayClie
 }
 
 // GetAwsAPIGatewayClient returns AWS API Gateway client.
-func GetAwsAPIGatewayClient(ctx context.Context) (*apigateway.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsAPIGatewayClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*apigateway.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -88,6 +85,7 @@ func (tm AwsAPIGatewayTagsMapper) GetTagsMapping(ctx context.Context) (map[strin
 				tags[j] = fmt.Sprintf("%s:%s", strings.ToLower(k), strings.ToLower(v))
 				j++
 			}
+			tags = append(tags, awsIntegrationTag("apiname", *api.Name))
 
 			mapping[*api.Name] = tags
 		}
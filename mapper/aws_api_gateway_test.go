@@ -80,10 +80,10 @@ func Test_AwsAPIGateway_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"api1":  []string{"key1:val1"},
-				"api2":  []string{"key2:val2"},
-				"api10": []string{"key10:val10"},
-				"api20": []string{"key20:val20"},
+				"api1":  []string{"key1:val1", "apiname:api1"},
+				"api2":  []string{"key2:val2", "apiname:api2"},
+				"api10": []string{"key10:val10", "apiname:api10"},
+				"api20": []string{"key20:val20", "apiname:api20"},
 			},
 			err: nil,
 		},
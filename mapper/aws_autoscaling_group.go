@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	goCache "github.com/patrickmn/go-cache"
@@ -40,10 +39,8 @@ func BuildAwsAutoScalingGroupTagsMapper(cache *goCache.Cache, client AwsAutoScal
 }
 
 // GetAwsAutoScalingGroupClient returns AWS AutoScalingGroup client.
-func GetAwsAutoScalingGroupClient(ctx context.Context) (*autoscaling.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsAutoScalingGroupClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*autoscaling.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -83,6 +80,7 @@ func (tm AwsAutoScalingGroupTagsMapper) GetTagsMapping(ctx context.Context) (map
 			for j, tag := range asg.Tags {
 				tags[j] = fmt.Sprintf("%s:%s", strings.ToLower(*tag.Key), strings.ToLower(*tag.Value))
 			}
+			tags = append(tags, awsIntegrationTag("autoscalinggroupname", *asg.AutoScalingGroupName))
 			mapping[*asg.AutoScalingGroupName] = tags
 		}
 
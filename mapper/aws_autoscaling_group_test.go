@@ -92,10 +92,10 @@ func Test_AwsAutoScalingGroup_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"gateway1":  []string{"key1:val1"},
-				"gateway2":  []string{"key2:val2"},
-				"gateway10": []string{"key10:val10"},
-				"gateway20": []string{"key20:val20"},
+				"gateway1":  []string{"key1:val1", "autoscalinggroupname:gateway1"},
+				"gateway2":  []string{"key2:val2", "autoscalinggroupname:gateway2"},
+				"gateway10": []string{"key10:val10", "autoscalinggroupname:gateway10"},
+				"gateway20": []string{"key20:val20", "autoscalinggroupname:gateway20"},
 			},
 			err: nil,
 		},
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
 	goCache "github.com/patrickmn/go-cache"
@@ -37,10 +36,8 @@ type AwsClbTagsMapper struct {
 }
 
 // GetAwsClbClient returns AWS CLB client.
-func GetAwsClbClient(ctx context.Context) (*elasticloadbalancing.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsClbClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*elasticloadbalancing.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -113,6 +110,7 @@ func (tm AwsClbTagsMapper) GetTagsMapping(ctx context.Context) (map[string]Tags,
 
 				idx := iter*i + j
 				lb := output.LoadBalancerDescriptions[idx]
+				tags = append(tags, awsIntegrationTag("loadbalancername", *lb.LoadBalancerName))
 				mapping[*lb.LoadBalancerName] = tags
 			}
 		}
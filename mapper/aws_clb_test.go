@@ -100,10 +100,10 @@ func Test_AwsClb_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"lb1":  []string{"key1:val1", "key2:val2"},
-				"lb2":  []string{"key1:val1", "key2:val2"},
-				"lb10": []string{"key1:val1", "key2:val2"},
-				"lb20": []string{"key1:val1", "key2:val2"},
+				"lb1":  []string{"key1:val1", "key2:val2", "loadbalancername:lb1"},
+				"lb2":  []string{"key1:val1", "key2:val2", "loadbalancername:lb2"},
+				"lb10": []string{"key1:val1", "key2:val2", "loadbalancername:lb10"},
+				"lb20": []string{"key1:val1", "key2:val2", "loadbalancername:lb20"},
 			},
 			err: nil,
 		},
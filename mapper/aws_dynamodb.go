@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	goCache "github.com/patrickmn/go-cache"
@@ -50,10 +49,8 @@ func BuildAwsDynamoDBTagsMapper(cache *goCache.Cache, client AwsDynamoDBClient)
 }
 
 // GetAwsDynamoDBClient returns AWS DynamoDB client.
-func GetAwsDynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsDynamoDBClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*dynamodb.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -122,6 +119,7 @@ func (tm AwsDynamoDBTagsMapper) GetTagsMapping(ctx context.Context) (map[string]
 				tagMarker = tagsOutput.NextToken
 			}
 
+			tags = append(tags, awsIntegrationTag("tablename", name))
 			mapping[name] = tags
 		}
 
@@ -113,10 +113,10 @@ func Test_AwsDynamoDB_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"table1":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"table2":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"table10": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"table20": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
+				"table1":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "tablename:table1"},
+				"table2":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "tablename:table2"},
+				"table10": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "tablename:table10"},
+				"table20": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "tablename:table20"},
 			},
 			err: nil,
 		},
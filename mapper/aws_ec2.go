@@ -0,0 +1,116 @@
+package mapper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	goCache "github.com/patrickmn/go-cache"
+
+	"github.com/terakoya76/modd/datadog"
+)
+
+const awsEc2CacheKey string = string(datadog.AwsEc2)
+
+// AwsEc2Client is abstract interface of *ec2.Client.
+type AwsEc2Client interface {
+	DescribeInstances(
+		ctx context.Context,
+		params *ec2.DescribeInstancesInput,
+		optFns ...func(*ec2.Options),
+	) (*ec2.DescribeInstancesOutput, error)
+}
+
+// AwsEc2TagsMapper implements TagsMapper for AWS EC2.
+type AwsEc2TagsMapper struct {
+	cache          *goCache.Cache
+	client         AwsEc2Client
+	instanceStates []string
+}
+
+// GetAwsEc2Client returns AWS EC2 client.
+func GetAwsEc2Client(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*ec2.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return ec2.NewFromConfig(cfg), nil
+}
+
+// BuildAwsEc2TagsMapper builds AwsEc2TagsMapper from args.
+func BuildAwsEc2TagsMapper(cache *goCache.Cache, client AwsEc2Client, instanceStates []string) AwsEc2TagsMapper {
+	return AwsEc2TagsMapper{
+		cache:          cache,
+		client:         client,
+		instanceStates: instanceStates,
+	}
+}
+
+// GetTagsMapping returns the latest tags mapping.
+func (tm AwsEc2TagsMapper) GetTagsMapping(ctx context.Context) (map[string]Tags, error) {
+	if cv, found := tm.cache.Get(awsEc2CacheKey); found {
+		mapping := cv.(map[string]Tags)
+		return mapping, nil
+	}
+
+	mapping := make(map[string]Tags)
+
+	// https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/ec2#DescribeInstancesInput
+	token := aws.String("")
+	maxResults := aws.Int32(1000)
+
+	// When no state is specified, all instances are fetched regardless of their state.
+	filters := []types.Filter{}
+	if len(tm.instanceStates) > 0 {
+		filters = append(filters, types.Filter{
+			Name:   aws.String("instance-state-name"),
+			Values: tm.instanceStates,
+		})
+	}
+
+	for token != nil {
+		// NextToken could not be empty string
+		var input ec2.DescribeInstancesInput
+		if *token == "" {
+			input = ec2.DescribeInstancesInput{Filters: filters, MaxResults: maxResults}
+		} else {
+			input = ec2.DescribeInstancesInput{Filters: filters, MaxResults: maxResults, NextToken: token}
+		}
+
+		output, err := tm.client.DescribeInstances(ctx, &input)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		for i := 0; i < len(output.Reservations); i++ {
+			reservation := output.Reservations[i]
+
+			for j := 0; j < len(reservation.Instances); j++ {
+				instance := reservation.Instances[j]
+
+				tags := make(Tags, len(instance.Tags))
+				for k, tag := range instance.Tags {
+					tags[k] = fmt.Sprintf("%s:%s", strings.ToLower(*tag.Key), strings.ToLower(*tag.Value))
+				}
+				tags = append(tags, awsIntegrationTag("host", *instance.InstanceId))
+				if instance.InstanceType != "" {
+					tags = append(tags, awsIntegrationTag("instance-type", string(instance.InstanceType)))
+				}
+				if instance.Placement != nil && instance.Placement.AvailabilityZone != nil {
+					tags = append(tags, awsIntegrationTag("availability-zone", *instance.Placement.AvailabilityZone))
+				}
+				mapping[*instance.InstanceId] = tags
+			}
+		}
+
+		token = output.NextToken
+	}
+
+	tm.cache.Set(awsEc2CacheKey, mapping, goCache.DefaultExpiration)
+	return mapping, nil
+}
@@ -0,0 +1,159 @@
+package mapper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go/middleware"
+	goCache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/mapper"
+)
+
+// dummyAwsEc2Client implements AwsEc2Client interface for faking AWS API.
+type dummyAwsEc2Client struct{}
+
+// DescribeInstances implements AwsEc2Client for dummyAwsEc2Client.
+func (c *dummyAwsEc2Client) DescribeInstances(
+	_ context.Context,
+	params *ec2.DescribeInstancesInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeInstancesOutput, error) {
+	var output ec2.DescribeInstancesOutput
+
+	if params.NextToken != nil {
+		output = ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{
+				{
+					Instances: []types.Instance{
+						{
+							InstanceId: aws.String("i-10"),
+							State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+							Tags: []types.Tag{
+								{
+									Key:   aws.String("key10"),
+									Value: aws.String("val10"),
+								},
+							},
+						},
+						{
+							InstanceId: aws.String("i-20"),
+							State:      &types.InstanceState{Name: types.InstanceStateNameTerminated},
+							Tags: []types.Tag{
+								{
+									Key:   aws.String("key20"),
+									Value: aws.String("val20"),
+								},
+							},
+						},
+					},
+				},
+			},
+			NextToken:      nil,
+			ResultMetadata: middleware.Metadata{},
+		}
+	} else {
+		output = ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{
+				{
+					Instances: []types.Instance{
+						{
+							InstanceId: aws.String("i-1"),
+							State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+							Tags: []types.Tag{
+								{
+									Key:   aws.String("key1"),
+									Value: aws.String("val1"),
+								},
+							},
+						},
+						{
+							InstanceId: aws.String("i-2"),
+							State:      &types.InstanceState{Name: types.InstanceStateNameStopped},
+							Tags: []types.Tag{
+								{
+									Key:   aws.String("key2"),
+									Value: aws.String("val2"),
+								},
+							},
+						},
+					},
+				},
+			},
+			NextToken:      aws.String("next token"),
+			ResultMetadata: middleware.Metadata{},
+		}
+	}
+
+	// emulate the instance-state-name filter on the server side
+	for _, f := range params.Filters {
+		if *f.Name != "instance-state-name" {
+			continue
+		}
+
+		states := make(map[string]struct{}, len(f.Values))
+		for _, v := range f.Values {
+			states[v] = struct{}{}
+		}
+
+		for i := 0; i < len(output.Reservations); i++ {
+			instances := make([]types.Instance, 0, len(output.Reservations[i].Instances))
+			for _, instance := range output.Reservations[i].Instances {
+				if _, ok := states[string(instance.State.Name)]; ok {
+					instances = append(instances, instance)
+				}
+			}
+			output.Reservations[i].Instances = instances
+		}
+	}
+
+	return &output, nil
+}
+
+func Test_AwsEc2_GetTagsMapping(t *testing.T) {
+	cases := []struct {
+		name           string
+		instanceStates []string
+		expected       map[string]mapper.Tags
+		err            error
+	}{
+		{
+			name:           "when only running instances are targeted",
+			instanceStates: []string{"pending", "running"},
+			expected: map[string]mapper.Tags{
+				"i-1":  []string{"key1:val1", "host:i-1"},
+				"i-10": []string{"key10:val10", "host:i-10"},
+			},
+			err: nil,
+		},
+		{
+			name:           "when any instance state is targeted",
+			instanceStates: []string{},
+			expected: map[string]mapper.Tags{
+				"i-1":  []string{"key1:val1", "host:i-1"},
+				"i-2":  []string{"key2:val2", "host:i-2"},
+				"i-10": []string{"key10:val10", "host:i-10"},
+				"i-20": []string{"key20:val20", "host:i-20"},
+			},
+			err: nil,
+		},
+	}
+
+	for _, c := range cases {
+		cache := goCache.New(60*time.Minute, 10*time.Minute)
+		client := dummyAwsEc2Client{}
+		m := mapper.BuildAwsEc2TagsMapper(cache, &client, c.instanceStates)
+		actual, err := m.GetTagsMapping(context.TODO())
+		if !assert.Equal(t, c.err, err) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.err, err)
+		}
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
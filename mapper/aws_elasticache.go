@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/elasticache"
 	goCache "github.com/patrickmn/go-cache"
@@ -45,10 +44,8 @@ func BuildAwsElastiCacheTagsMapper(cache *goCache.Cache, client AwsElastiCacheCl
 }
 
 // GetAwsElastiCacheClient returns AWS ElastiCache client.
-func GetAwsElastiCacheClient(ctx context.Context) (*elasticache.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsElastiCacheClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*elasticache.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -94,6 +91,10 @@ func (tm AwsElastiCacheTagsMapper) GetTagsMapping(ctx context.Context) (map[stri
 			for j, tag := range tagsOutput.TagList {
 				tags[j] = fmt.Sprintf("%s:%s", strings.ToLower(*tag.Key), strings.ToLower(*tag.Value))
 			}
+			tags = append(tags, awsIntegrationTag("cacheclusterid", *cluster.CacheClusterId))
+			if cluster.Engine != nil {
+				tags = append(tags, awsIntegrationTag("engine", *cluster.Engine))
+			}
 			mapping[*cluster.CacheClusterId] = tags
 		}
 
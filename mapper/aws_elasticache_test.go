@@ -103,10 +103,10 @@ func Test_AwsElastiCache_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"cache1":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"cache2":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"cache10": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"cache20": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
+				"cache1":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "cacheclusterid:cache1"},
+				"cache2":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "cacheclusterid:cache2"},
+				"cache10": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "cacheclusterid:cache10"},
+				"cache20": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "cacheclusterid:cache20"},
 			},
 			err: nil,
 		},
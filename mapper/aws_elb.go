@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	goCache "github.com/patrickmn/go-cache"
@@ -37,10 +36,8 @@ type AwsElbTagsMapper struct {
 }
 
 // GetAwsElbClient returns AWS ALB/NLB client.
-func GetAwsElbClient(ctx context.Context) (*elasticloadbalancingv2.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsElbClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*elasticloadbalancingv2.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -113,6 +110,13 @@ func (tm AwsElbTagsMapper) GetTagsMapping(ctx context.Context) (map[string]Tags,
 
 				idx := iter*i + j
 				lb := output.LoadBalancers[idx]
+
+				// cf. arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188
+				if pos := strings.Index(*lb.LoadBalancerArn, ":loadbalancer/"); pos >= 0 {
+					lbID := (*lb.LoadBalancerArn)[pos+len(":loadbalancer/"):]
+					tags = append(tags, awsIntegrationTag("loadbalancer", lbID))
+				}
+				tags = append(tags, awsIntegrationTag("name", *lb.LoadBalancerName))
 				mapping[*lb.LoadBalancerName] = tags
 			}
 		}
@@ -30,11 +30,11 @@ func (c *dummyAwsElbClient) DescribeLoadBalancers(
 		output = elasticloadbalancingv2.DescribeLoadBalancersOutput{
 			LoadBalancers: []types.LoadBalancer{
 				{
-					LoadBalancerArn:  aws.String("lb arn 10"),
+					LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/lb10/1010"),
 					LoadBalancerName: aws.String("lb10"),
 				},
 				{
-					LoadBalancerArn:  aws.String("lb arn 20"),
+					LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/lb20/2020"),
 					LoadBalancerName: aws.String("lb20"),
 				},
 			},
@@ -45,11 +45,11 @@ func (c *dummyAwsElbClient) DescribeLoadBalancers(
 		output = elasticloadbalancingv2.DescribeLoadBalancersOutput{
 			LoadBalancers: []types.LoadBalancer{
 				{
-					LoadBalancerArn:  aws.String("lb arn 1"),
+					LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/lb1/11"),
 					LoadBalancerName: aws.String("lb1"),
 				},
 				{
-					LoadBalancerArn:  aws.String("lb arn 2"),
+					LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/lb2/22"),
 					LoadBalancerName: aws.String("lb2"),
 				},
 			},
@@ -104,10 +104,10 @@ func Test_AwsElb_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"lb1":  []string{"key1:val1", "key2:val2"},
-				"lb2":  []string{"key1:val1", "key2:val2"},
-				"lb10": []string{"key1:val1", "key2:val2"},
-				"lb20": []string{"key1:val1", "key2:val2"},
+				"lb1":  []string{"key1:val1", "key2:val2", "loadbalancer:app/lb1/11", "name:lb1"},
+				"lb2":  []string{"key1:val1", "key2:val2", "loadbalancer:app/lb2/22", "name:lb2"},
+				"lb10": []string{"key1:val1", "key2:val2", "loadbalancer:app/lb10/1010", "name:lb10"},
+				"lb20": []string{"key1:val1", "key2:val2", "loadbalancer:app/lb20/2020", "name:lb20"},
 			},
 			err: nil,
 		},
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/firehose"
 	goCache "github.com/patrickmn/go-cache"
@@ -45,10 +44,8 @@ func BuildAwsFirehoseTagsMapper(cache *goCache.Cache, client AwsFirehoseClient)
 }
 
 // GetAwsFirehoseClient returns AWS Firehose client.
-func GetAwsFirehoseClient(ctx context.Context) (*firehose.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsFirehoseClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*firehose.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -125,6 +122,7 @@ func (tm AwsFirehoseTagsMapper) GetTagsMapping(ctx context.Context) (map[string]
 				hasMoreTag = *tagsOutput.HasMoreTags
 			}
 
+			tags = append(tags, awsIntegrationTag("deliverystreamname", name))
 			mapping[name] = tags
 		}
 
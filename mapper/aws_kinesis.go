@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis"
 	goCache "github.com/patrickmn/go-cache"
@@ -45,10 +44,8 @@ func BuildAwsKinesisTagsMapper(cache *goCache.Cache, client AwsKinesisClient) Aw
 }
 
 // GetAwsKinesisClient returns AWS Kinesis client.
-func GetAwsKinesisClient(ctx context.Context) (*kinesis.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsKinesisClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*kinesis.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -125,6 +122,7 @@ func (tm AwsKinesisTagsMapper) GetTagsMapping(ctx context.Context) (map[string]T
 				hasMoreTag = *tagsOutput.HasMoreTags
 			}
 
+			tags = append(tags, awsIntegrationTag("streamname", name))
 			mapping[name] = tags
 		}
 
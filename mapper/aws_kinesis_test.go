@@ -97,10 +97,10 @@ func Test_AwsKinesis_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"stream1":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"stream2":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"stream10": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
-				"stream20": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20"},
+				"stream1":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "streamname:stream1"},
+				"stream2":  []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "streamname:stream2"},
+				"stream10": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "streamname:stream10"},
+				"stream20": []string{"key1:val1", "key2:val2", "key10:val10", "key20:val20", "streamname:stream20"},
 			},
 			err: nil,
 		},
@@ -0,0 +1,111 @@
+package mapper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	goCache "github.com/patrickmn/go-cache"
+
+	"github.com/terakoya76/modd/datadog"
+)
+
+const awsLambdaCacheKey string = string(datadog.AwsLambda)
+
+// AwsLambdaClient is abstract interface of *lambda.Client.
+type AwsLambdaClient interface {
+	ListFunctions(
+		ctx context.Context,
+		params *lambda.ListFunctionsInput,
+		optFns ...func(*lambda.Options),
+	) (*lambda.ListFunctionsOutput, error)
+	ListTags(
+		ctx context.Context,
+		params *lambda.ListTagsInput,
+		optFns ...func(*lambda.Options),
+	) (*lambda.ListTagsOutput, error)
+}
+
+// AwsLambdaTagsMapper implements TagsMapper for AWS Lambda.
+type AwsLambdaTagsMapper struct {
+	cache  *goCache.Cache
+	client AwsLambdaClient
+}
+
+// GetAwsLambdaClient returns AWS Lambda client.
+func GetAwsLambdaClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*lambda.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return lambda.NewFromConfig(cfg), nil
+}
+
+// BuildAwsLambdaTagsMapper builds AwsLambdaTagsMapper from args.
+func BuildAwsLambdaTagsMapper(cache *goCache.Cache, client AwsLambdaClient) AwsLambdaTagsMapper {
+	return AwsLambdaTagsMapper{
+		cache:  cache,
+		client: client,
+	}
+}
+
+// GetTagsMapping returns the latest tags mapping.
+func (tm AwsLambdaTagsMapper) GetTagsMapping(ctx context.Context) (map[string]Tags, error) {
+	if cv, found := tm.cache.Get(awsLambdaCacheKey); found {
+		mapping := cv.(map[string]Tags)
+		return mapping, nil
+	}
+
+	mapping := make(map[string]Tags)
+
+	// https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/lambda#ListFunctionsInput
+	marker := aws.String("")
+	maxItems := aws.Int32(50)
+
+	for marker != nil {
+		// Marker could not be empty string
+		var input lambda.ListFunctionsInput
+		if *marker == "" {
+			input = lambda.ListFunctionsInput{MaxItems: maxItems}
+		} else {
+			input = lambda.ListFunctionsInput{MaxItems: maxItems, Marker: marker}
+		}
+
+		output, err := tm.client.ListFunctions(ctx, &input)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		for i := 0; i < len(output.Functions); i++ {
+			function := output.Functions[i]
+			tagsInput := lambda.ListTagsInput{Resource: function.FunctionArn}
+			tagsOutput, err := tm.client.ListTags(ctx, &tagsInput)
+			if err != nil {
+				return nil, fmt.Errorf("%w", err)
+			}
+
+			// https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/lambda#ListTagsOutput
+			tags := make(Tags, len(tagsOutput.Tags))
+			j := 0
+			for k, v := range tagsOutput.Tags {
+				tags[j] = fmt.Sprintf("%s:%s", strings.ToLower(k), strings.ToLower(v))
+				j++
+			}
+			tags = append(tags, awsIntegrationTag("functionname", *function.FunctionName))
+			if function.Runtime != "" {
+				tags = append(tags, awsIntegrationTag("runtime", string(function.Runtime)))
+			}
+
+			mapping[*function.FunctionName] = tags
+		}
+
+		marker = output.NextMarker
+	}
+
+	tm.cache.Set(awsLambdaCacheKey, mapping, goCache.DefaultExpiration)
+	return mapping, nil
+}
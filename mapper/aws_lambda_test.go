@@ -0,0 +1,115 @@
+package mapper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/smithy-go/middleware"
+	goCache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/mapper"
+)
+
+// dummyAwsLambdaClient implements AwsLambdaClient interface for faking AWS API.
+type dummyAwsLambdaClient struct{}
+
+// ListFunctions implements AwsLambdaClient for dummyAwsLambdaClient.
+func (c *dummyAwsLambdaClient) ListFunctions(
+	_ context.Context,
+	params *lambda.ListFunctionsInput,
+	_ ...func(*lambda.Options),
+) (*lambda.ListFunctionsOutput, error) {
+	var output lambda.ListFunctionsOutput
+
+	if params.Marker != nil {
+		output = lambda.ListFunctionsOutput{
+			Functions: []types.FunctionConfiguration{
+				{
+					FunctionName: aws.String("function10"),
+					FunctionArn:  aws.String("arn:aws:lambda:ap-northeast-1:123456789012:function:function10"),
+				},
+				{
+					FunctionName: aws.String("function20"),
+					FunctionArn:  aws.String("arn:aws:lambda:ap-northeast-1:123456789012:function:function20"),
+				},
+			},
+			NextMarker:     nil,
+			ResultMetadata: middleware.Metadata{},
+		}
+	} else {
+		output = lambda.ListFunctionsOutput{
+			Functions: []types.FunctionConfiguration{
+				{
+					FunctionName: aws.String("function1"),
+					FunctionArn:  aws.String("arn:aws:lambda:ap-northeast-1:123456789012:function:function1"),
+				},
+				{
+					FunctionName: aws.String("function2"),
+					FunctionArn:  aws.String("arn:aws:lambda:ap-northeast-1:123456789012:function:function2"),
+				},
+			},
+			NextMarker:     aws.String("next marker"),
+			ResultMetadata: middleware.Metadata{},
+		}
+	}
+
+	return &output, nil
+}
+
+// ListTags implements AwsLambdaClient for dummyAwsLambdaClient.
+func (c *dummyAwsLambdaClient) ListTags(
+	_ context.Context,
+	_ *lambda.ListTagsInput,
+	_ ...func(*lambda.Options),
+) (*lambda.ListTagsOutput, error) {
+	output := lambda.ListTagsOutput{
+		Tags: map[string]string{
+			"key1": "val1",
+			"key2": "val2",
+		},
+		ResultMetadata: middleware.Metadata{},
+	}
+
+	return &output, nil
+}
+
+func Test_AwsLambda_GetTagsMapping(t *testing.T) {
+	cache := goCache.New(60*time.Minute, 10*time.Minute)
+
+	cases := []struct {
+		name     string
+		expected map[string]mapper.Tags
+		err      error
+	}{
+		{
+			name: "fake test",
+			expected: map[string]mapper.Tags{
+				"function1":  []string{"key1:val1", "key2:val2", "functionname:function1"},
+				"function2":  []string{"key1:val1", "key2:val2", "functionname:function2"},
+				"function10": []string{"key1:val1", "key2:val2", "functionname:function10"},
+				"function20": []string{"key1:val1", "key2:val2", "functionname:function20"},
+			},
+			err: nil,
+		},
+	}
+
+	for _, c := range cases {
+		client := dummyAwsLambdaClient{}
+		m := mapper.BuildAwsLambdaTagsMapper(cache, &client)
+		actual, err := m.GetTagsMapping(context.TODO())
+		if !assert.Equal(t, c.err, err) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.err, err)
+		}
+
+		for k, v := range c.expected {
+			if !assert.ElementsMatch(t, v, actual[k]) {
+				t.Errorf("case: %s is failed with the key %s, expected: %+v, actual: %+v\n", c.name, k, v, actual[k])
+			}
+		}
+	}
+}
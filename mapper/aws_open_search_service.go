@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/elasticsearchservice"
 	goCache "github.com/patrickmn/go-cache"
@@ -50,10 +48,8 @@ func BuildAwsOpenSearchServiceTagsMapper(cache *goCache.Cache, client AwsOpenSea
 }
 
 // GetAwsOpenSearchServiceClient returns AWS OpenSearch Service client.
-func GetAwsOpenSearchServiceClient(ctx context.Context) (*elasticsearchservice.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsOpenSearchServiceClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*elasticsearchservice.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -108,6 +104,7 @@ func (tm AwsOpenSearchServiceTagsMapper) GetTagsMapping(ctx context.Context) (ma
 			for k, tag := range tagsOutput.TagList {
 				tags[k] = fmt.Sprintf("%s:%s", strings.ToLower(*tag.Key), strings.ToLower(*tag.Value))
 			}
+			tags = append(tags, awsIntegrationTag("domainname", *domain.DomainName))
 			mapping[*domain.DomainName] = tags
 		}
 	}
@@ -98,8 +98,8 @@ func Test_AwsOpenSearchService_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"domain1": []string{"key1:val1", "key2:val2"},
-				"domain2": []string{"key1:val1", "key2:val2"},
+				"domain1": []string{"key1:val1", "key2:val2", "domainname:domain1"},
+				"domain2": []string{"key1:val1", "key2:val2", "domainname:domain2"},
 			},
 			err: nil,
 		},
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	goCache "github.com/patrickmn/go-cache"
@@ -32,10 +31,8 @@ type AwsRdsTagsMapper struct {
 }
 
 // GetAwsRdsClient returns AWS RDS client.
-func GetAwsRdsClient(ctx context.Context) (*rds.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsRdsClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*rds.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -84,6 +81,16 @@ func (tm AwsRdsTagsMapper) GetTagsMapping(ctx context.Context) (map[string]Tags,
 			for j, tag := range db.TagList {
 				tags[j] = fmt.Sprintf("%s:%s", strings.ToLower(*tag.Key), strings.ToLower(*tag.Value))
 			}
+			tags = append(tags, awsIntegrationTag("dbinstanceidentifier", *db.DBInstanceIdentifier))
+			if db.DBInstanceClass != nil {
+				tags = append(tags, awsIntegrationTag("dbinstanceclass", *db.DBInstanceClass))
+			}
+			if db.Engine != nil {
+				tags = append(tags, awsIntegrationTag("engine", *db.Engine))
+			}
+			if db.DBClusterIdentifier != nil {
+				tags = append(tags, awsIntegrationTag("dbclusteridentifier", *db.DBClusterIdentifier))
+			}
 			mapping[*db.DBInstanceIdentifier] = tags
 		}
 
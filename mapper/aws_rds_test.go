@@ -64,6 +64,9 @@ func (c *dummyAwsRdsClient) DescribeDBInstances(
 			DBInstances: []types.DBInstance{
 				{
 					DBInstanceIdentifier: aws.String("db1"),
+					DBInstanceClass:      aws.String("db.r5.large"),
+					Engine:               aws.String("aurora-postgresql"),
+					DBClusterIdentifier:  aws.String("cluster1"),
 					TagList: []types.Tag{
 						{
 							Key:   aws.String("key1"),
@@ -108,10 +111,10 @@ func Test_AwsRds_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"db1":  []string{"key1:val1", "key2:val2"},
-				"db2":  []string{"key3:val3", "key4:val4"},
-				"db10": []string{"key10:val10", "key20:val20"},
-				"db20": []string{"key30:val30", "key40:val40"},
+				"db1":  []string{"key1:val1", "key2:val2", "dbinstanceidentifier:db1", "dbinstanceclass:db.r5.large", "engine:aurora-postgresql", "dbclusteridentifier:cluster1"},
+				"db2":  []string{"key3:val3", "key4:val4", "dbinstanceidentifier:db2"},
+				"db10": []string{"key10:val10", "key20:val20", "dbinstanceidentifier:db10"},
+				"db20": []string{"key30:val30", "key40:val40", "dbinstanceidentifier:db20"},
 			},
 			err: nil,
 		},
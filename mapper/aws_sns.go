@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	goCache "github.com/patrickmn/go-cache"
@@ -37,10 +36,8 @@ type AwsSnsTagsMapper struct {
 }
 
 // GetAwsSnsClient returns AWS SNS client.
-func GetAwsSnsClient(ctx context.Context) (*sns.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsSnsClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*sns.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -97,6 +94,7 @@ func (tm AwsSnsTagsMapper) GetTagsMapping(ctx context.Context) (map[string]Tags,
 
 			arn := *topic.TopicArn
 			name := arn[strings.LastIndex(arn, ":")+1:]
+			tags = append(tags, awsIntegrationTag("topicname", name))
 			mapping[name] = tags
 		}
 
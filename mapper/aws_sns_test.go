@@ -91,10 +91,10 @@ func Test_AwsSns_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"topic1":  []string{"key1:val1", "key2:val2"},
-				"topic2":  []string{"key1:val1", "key2:val2"},
-				"topic10": []string{"key1:val1", "key2:val2"},
-				"topic20": []string{"key1:val1", "key2:val2"},
+				"topic1":  []string{"key1:val1", "key2:val2", "topicname:topic1"},
+				"topic2":  []string{"key1:val1", "key2:val2", "topicname:topic2"},
+				"topic10": []string{"key1:val1", "key2:val2", "topicname:topic10"},
+				"topic20": []string{"key1:val1", "key2:val2", "topicname:topic20"},
 			},
 			err: nil,
 		},
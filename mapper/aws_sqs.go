@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	goCache "github.com/patrickmn/go-cache"
@@ -37,10 +36,8 @@ type AwsSqsTagsMapper struct {
 }
 
 // GetAwsSqsClient returns AWS SQS client.
-func GetAwsSqsClient(ctx context.Context) (*sqs.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsSqsClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*sqs.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -100,6 +97,7 @@ func (tm AwsSqsTagsMapper) GetTagsMapping(ctx context.Context) (map[string]Tags,
 			}
 
 			queueName := queueURL[strings.LastIndex(queueURL, "/")+1:]
+			tags = append(tags, awsIntegrationTag("queuename", queueName))
 			mapping[queueName] = tags
 		}
 
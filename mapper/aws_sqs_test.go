@@ -76,10 +76,10 @@ func Test_AwsSqs_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"queue1":  []string{"key1:val1", "key2:val2"},
-				"queue2":  []string{"key1:val1", "key2:val2"},
-				"queue10": []string{"key1:val1", "key2:val2"},
-				"queue20": []string{"key1:val1", "key2:val2"},
+				"queue1":  []string{"key1:val1", "key2:val2", "queuename:queue1"},
+				"queue2":  []string{"key1:val1", "key2:val2", "queuename:queue2"},
+				"queue10": []string{"key1:val1", "key2:val2", "queuename:queue10"},
+				"queue20": []string{"key1:val1", "key2:val2", "queuename:queue20"},
 			},
 			err: nil,
 		},
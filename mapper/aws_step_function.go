@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sfn"
 	goCache "github.com/patrickmn/go-cache"
@@ -37,10 +36,8 @@ type AwsStepFunctionTagsMapper struct {
 }
 
 // GetAwsStepFunctionClient returns AWS StepFunction client.
-func GetAwsStepFunctionClient(ctx context.Context) (*sfn.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return retry.AddWithMaxAttempts(retry.NewStandard(), 10)
-	}))
+func GetAwsStepFunctionClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*sfn.Client, error) {
+	cfg, err := LoadAwsConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -98,6 +95,8 @@ func (tm AwsStepFunctionTagsMapper) GetTagsMapping(ctx context.Context) (map[str
 			for j, tag := range tagsOutput.Tags {
 				tags[j] = fmt.Sprintf("%s:%s", strings.ToLower(*tag.Key), strings.ToLower(*tag.Value))
 			}
+			tags = append(tags, awsIntegrationTag("statemachinearn", *sm.StateMachineArn))
+			tags = append(tags, awsIntegrationTag("statemachinename", *sm.Name))
 			mapping[*sm.Name] = tags
 		}
 
@@ -31,11 +31,11 @@ func (c *dummyAwsStepFunctionClient) ListStateMachines(
 			StateMachines: []types.StateMachineListItem{
 				{
 					Name:            aws.String("sfn10"),
-					StateMachineArn: aws.String("sfn arn 10"),
+					StateMachineArn: aws.String("arn:aws:states:us-east-1:123456789012:stateMachine:sfn10"),
 				},
 				{
 					Name:            aws.String("sfn20"),
-					StateMachineArn: aws.String("sfn arn 20"),
+					StateMachineArn: aws.String("arn:aws:states:us-east-1:123456789012:stateMachine:sfn20"),
 				},
 			},
 			NextToken:      nil,
@@ -46,11 +46,11 @@ func (c *dummyAwsStepFunctionClient) ListStateMachines(
 			StateMachines: []types.StateMachineListItem{
 				{
 					Name:            aws.String("sfn1"),
-					StateMachineArn: aws.String("sfn arn 1"),
+					StateMachineArn: aws.String("arn:aws:states:us-east-1:123456789012:stateMachine:sfn1"),
 				},
 				{
 					Name:            aws.String("sfn2"),
-					StateMachineArn: aws.String("sfn arn 2"),
+					StateMachineArn: aws.String("arn:aws:states:us-east-1:123456789012:stateMachine:sfn2"),
 				},
 			},
 			NextToken:      aws.String("next token"),
@@ -95,10 +95,10 @@ func Test_AwsStepFunction_GetTagsMapping(t *testing.T) {
 		{
 			name: "fake test",
 			expected: map[string]mapper.Tags{
-				"sfn1":  []string{"key1:val1", "key2:val2"},
-				"sfn2":  []string{"key1:val1", "key2:val2"},
-				"sfn10": []string{"key1:val1", "key2:val2"},
-				"sfn20": []string{"key1:val1", "key2:val2"},
+				"sfn1":  []string{"key1:val1", "key2:val2", "statemachinearn:arn:aws:states:us-east-1:123456789012:statemachine:sfn1", "statemachinename:sfn1"},
+				"sfn2":  []string{"key1:val1", "key2:val2", "statemachinearn:arn:aws:states:us-east-1:123456789012:statemachine:sfn2", "statemachinename:sfn2"},
+				"sfn10": []string{"key1:val1", "key2:val2", "statemachinearn:arn:aws:states:us-east-1:123456789012:statemachine:sfn10", "statemachinename:sfn10"},
+				"sfn20": []string{"key1:val1", "key2:val2", "statemachinearn:arn:aws:states:us-east-1:123456789012:statemachine:sfn20", "statemachinename:sfn20"},
 			},
 			err: nil,
 		},
@@ -0,0 +1,213 @@
+package mapper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go/middleware"
+	goCache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/mapper"
+)
+
+// dummyAwsRegionClient implements AwsRegionClient interface for faking AWS API.
+type dummyAwsRegionClient struct{}
+
+// DescribeRegions implements AwsRegionClient for dummyAwsRegionClient.
+func (c *dummyAwsRegionClient) DescribeRegions(
+	_ context.Context,
+	_ *ec2.DescribeRegionsInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeRegionsOutput, error) {
+	output := ec2.DescribeRegionsOutput{
+		Regions: []types.Region{
+			{RegionName: aws.String("ap-northeast-1")},
+			{RegionName: aws.String("us-east-1")},
+		},
+		ResultMetadata: middleware.Metadata{},
+	}
+
+	return &output, nil
+}
+
+func Test_ResolveAwsRegions(t *testing.T) {
+	cases := []struct {
+		name     string
+		regions  []string
+		expected []string
+	}{
+		{
+			name:     "when no region is specified",
+			regions:  []string{},
+			expected: []string{"us-west-2"},
+		},
+		{
+			name:     "when regions are specified",
+			regions:  []string{"us-east-1", "eu-west-1"},
+			expected: []string{"us-east-1", "eu-west-1"},
+		},
+		{
+			name:     "when all regions are specified",
+			regions:  []string{"all"},
+			expected: []string{"ap-northeast-1", "us-east-1"},
+		},
+		{
+			name:     "when all regions are specified with duplicated region",
+			regions:  []string{"us-east-1", "all"},
+			expected: []string{"us-east-1", "ap-northeast-1"},
+		},
+	}
+
+	for _, c := range cases {
+		client := dummyAwsRegionClient{}
+		actual, err := mapper.ResolveAwsRegions(context.TODO(), &client, "us-west-2", c.regions)
+		if !assert.Nil(t, err) {
+			t.Errorf("case: %s is failed, err: %+v\n", c.name, err)
+		}
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_GetAwsAccounts(t *testing.T) {
+	cases := []struct {
+		name     string
+		accounts []string
+		roleName string
+		expected []mapper.AwsAccount
+		isErr    bool
+	}{
+		{
+			name:     "when no account is specified",
+			accounts: []string{},
+			roleName: "",
+			expected: []mapper.AwsAccount{{}},
+			isErr:    false,
+		},
+		{
+			name:     "when account IDs are specified",
+			accounts: []string{"123456789012", "210987654321"},
+			roleName: "modd",
+			expected: []mapper.AwsAccount{
+				{ID: "123456789012", RoleArn: "arn:aws:iam::123456789012:role/modd"},
+				{ID: "210987654321", RoleArn: "arn:aws:iam::210987654321:role/modd"},
+			},
+			isErr: false,
+		},
+		{
+			name:     "when role ARNs are specified",
+			accounts: []string{"arn:aws:iam::123456789012:role/my-role"},
+			roleName: "",
+			expected: []mapper.AwsAccount{
+				{ID: "123456789012", RoleArn: "arn:aws:iam::123456789012:role/my-role"},
+			},
+			isErr: false,
+		},
+		{
+			name:     "when account ID is specified without role name",
+			accounts: []string{"123456789012"},
+			roleName: "",
+			expected: nil,
+			isErr:    true,
+		},
+		{
+			name:     "when invalid value is specified",
+			accounts: []string{"my-account"},
+			roleName: "modd",
+			expected: nil,
+			isErr:    true,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := mapper.GetAwsAccounts(c.accounts, c.roleName)
+		if !assert.Equal(t, c.isErr, err != nil) {
+			t.Errorf("case: %s is failed, err: %+v\n", c.name, err)
+		}
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_AwsMultiTarget_GetTagsMapping(t *testing.T) {
+	cases := []struct {
+		name     string
+		targets  []mapper.AwsTarget
+		expected map[string]mapper.Tags
+	}{
+		{
+			name: "when single region of the default account",
+			targets: []mapper.AwsTarget{
+				{AccountID: "", Region: "us-east-1"},
+			},
+			expected: map[string]mapper.Tags{
+				"db1":  []string{"key1:val1", "key2:val2", "dbinstanceidentifier:db1", "dbinstanceclass:db.r5.large", "engine:aurora-postgresql", "dbclusteridentifier:cluster1", "region:us-east-1"},
+				"db2":  []string{"key3:val3", "key4:val4", "dbinstanceidentifier:db2", "region:us-east-1"},
+				"db10": []string{"key10:val10", "key20:val20", "dbinstanceidentifier:db10", "region:us-east-1"},
+				"db20": []string{"key30:val30", "key40:val40", "dbinstanceidentifier:db20", "region:us-east-1"},
+			},
+		},
+		{
+			name: "when multiple regions",
+			targets: []mapper.AwsTarget{
+				{AccountID: "", Region: "us-east-1"},
+				{AccountID: "", Region: "ap-northeast-1"},
+			},
+			expected: map[string]mapper.Tags{
+				"us-east-1/db1":       []string{"key1:val1", "key2:val2", "dbinstanceidentifier:db1", "dbinstanceclass:db.r5.large", "engine:aurora-postgresql", "dbclusteridentifier:cluster1", "region:us-east-1"},
+				"us-east-1/db2":       []string{"key3:val3", "key4:val4", "dbinstanceidentifier:db2", "region:us-east-1"},
+				"us-east-1/db10":      []string{"key10:val10", "key20:val20", "dbinstanceidentifier:db10", "region:us-east-1"},
+				"us-east-1/db20":      []string{"key30:val30", "key40:val40", "dbinstanceidentifier:db20", "region:us-east-1"},
+				"ap-northeast-1/db1":  []string{"key1:val1", "key2:val2", "dbinstanceidentifier:db1", "dbinstanceclass:db.r5.large", "engine:aurora-postgresql", "dbclusteridentifier:cluster1", "region:ap-northeast-1"},
+				"ap-northeast-1/db2":  []string{"key3:val3", "key4:val4", "dbinstanceidentifier:db2", "region:ap-northeast-1"},
+				"ap-northeast-1/db10": []string{"key10:val10", "key20:val20", "dbinstanceidentifier:db10", "region:ap-northeast-1"},
+				"ap-northeast-1/db20": []string{"key30:val30", "key40:val40", "dbinstanceidentifier:db20", "region:ap-northeast-1"},
+			},
+		},
+		{
+			name: "when multiple accounts",
+			targets: []mapper.AwsTarget{
+				{AccountID: "123456789012", Region: "us-east-1"},
+				{AccountID: "210987654321", Region: "us-east-1"},
+			},
+			expected: map[string]mapper.Tags{
+				"123456789012/db1":  []string{"key1:val1", "key2:val2", "dbinstanceidentifier:db1", "dbinstanceclass:db.r5.large", "engine:aurora-postgresql", "dbclusteridentifier:cluster1", "aws_account:123456789012", "region:us-east-1"},
+				"123456789012/db2":  []string{"key3:val3", "key4:val4", "dbinstanceidentifier:db2", "aws_account:123456789012", "region:us-east-1"},
+				"123456789012/db10": []string{"key10:val10", "key20:val20", "dbinstanceidentifier:db10", "aws_account:123456789012", "region:us-east-1"},
+				"123456789012/db20": []string{"key30:val30", "key40:val40", "dbinstanceidentifier:db20", "aws_account:123456789012", "region:us-east-1"},
+				"210987654321/db1":  []string{"key1:val1", "key2:val2", "dbinstanceidentifier:db1", "dbinstanceclass:db.r5.large", "engine:aurora-postgresql", "dbclusteridentifier:cluster1", "aws_account:210987654321", "region:us-east-1"},
+				"210987654321/db2":  []string{"key3:val3", "key4:val4", "dbinstanceidentifier:db2", "aws_account:210987654321", "region:us-east-1"},
+				"210987654321/db10": []string{"key10:val10", "key20:val20", "dbinstanceidentifier:db10", "aws_account:210987654321", "region:us-east-1"},
+				"210987654321/db20": []string{"key30:val30", "key40:val40", "dbinstanceidentifier:db20", "aws_account:210987654321", "region:us-east-1"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		mappers := make([]mapper.TargetTagsMapper, 0, len(c.targets))
+		for _, target := range c.targets {
+			cache := goCache.New(60*time.Minute, 10*time.Minute)
+			client := dummyAwsRdsClient{}
+			mappers = append(mappers, mapper.TargetTagsMapper{
+				Target:     target,
+				TagsMapper: mapper.BuildAwsRdsTagsMapper(cache, &client),
+			})
+		}
+
+		m := mapper.BuildAwsMultiTargetTagsMapper(mappers)
+		actual, err := m.GetTagsMapping(context.TODO())
+		if !assert.Nil(t, err) {
+			t.Errorf("case: %s is failed, err: %+v\n", c.name, err)
+		}
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	goCache "github.com/patrickmn/go-cache"
 
+	"github.com/terakoya76/modd/config"
 	"github.com/terakoya76/modd/datadog"
 )
 
@@ -18,15 +20,60 @@ type TagsMapper interface {
 	GetTagsMapping(ctx context.Context) (map[string]Tags, error)
 }
 
-// BuildTagsMapper build the proper TagsMapper implementation.
+// BuildTagsMapper build the proper TagsMapper implementation fanning out over the configured accounts and regions.
+// ctx is used to resolve the regions and the credentials of the accounts.
+func BuildTagsMapper(ctx context.Context, it datadog.IntegrationTarget, ic config.IntegrationConfig) (TagsMapper, error) {
+	if it == datadog.UnknownIntegration {
+		return nil, fmt.Errorf("unsupported IntegrationTarget")
+	}
+
+	accounts, err := GetAwsAccounts(ic.Accounts, ic.RoleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AWS accounts: %w", err)
+	}
+
+	regions, err := GetAwsRegions(ctx, ic.Regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AWS regions: %w", err)
+	}
+
+	mappers := make([]TargetTagsMapper, 0, len(accounts)*len(regions))
+	for _, account := range accounts {
+		accountOptFns := make([]func(*awsConfig.LoadOptions) error, 0, 1)
+		if account.RoleArn != "" {
+			optFn, err := WithAssumeRole(ctx, account.RoleArn)
+			if err != nil {
+				return nil, fmt.Errorf("failed to assume role %s: %w", account.RoleArn, err)
+			}
+			accountOptFns = append(accountOptFns, optFn)
+		}
+
+		for _, region := range regions {
+			optFns := append([]func(*awsConfig.LoadOptions) error{awsConfig.WithRegion(region)}, accountOptFns...)
+			m, err := buildAwsTagsMapper(ctx, it, ic, optFns...)
+			if err != nil {
+				return nil, fmt.Errorf("%w", err)
+			}
+
+			target := AwsTarget{AccountID: account.ID, Region: region}
+			mappers = append(mappers, TargetTagsMapper{Target: target, TagsMapper: m})
+		}
+	}
+
+	return BuildAwsMultiTargetTagsMapper(mappers), nil
+}
+
+// buildAwsTagsMapper build the proper TagsMapper implementation bound to the single AWS config.
 //
 //nolint:funlen,gocyclo
-func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
+func buildAwsTagsMapper(
+	ctx context.Context, it datadog.IntegrationTarget, ic config.IntegrationConfig, optFns ...func(*awsConfig.LoadOptions) error,
+) (TagsMapper, error) {
 	c := goCache.New(60*time.Minute, 10*time.Minute)
 
 	switch it {
 	case datadog.AwsAPIGateway:
-		client, err := GetAwsAPIGatewayClient(context.TODO())
+		client, err := GetAwsAPIGatewayClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -35,7 +82,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsAutoScalingGroup:
-		client, err := GetAwsAutoScalingGroupClient(context.TODO())
+		client, err := GetAwsAutoScalingGroupClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -44,7 +91,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsClb:
-		client, err := GetAwsClbClient(context.TODO())
+		client, err := GetAwsClbClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -53,7 +100,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsDynamoDB:
-		client, err := GetAwsDynamoDBClient(context.TODO())
+		client, err := GetAwsDynamoDBClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -61,8 +108,17 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		m := BuildAwsDynamoDBTagsMapper(c, client)
 		return m, nil
 
+	case datadog.AwsEc2:
+		client, err := GetAwsEc2Client(ctx, optFns...)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		m := BuildAwsEc2TagsMapper(c, client, ic.InstanceStates)
+		return m, nil
+
 	case datadog.AwsElastiCache:
-		client, err := GetAwsElastiCacheClient(context.TODO())
+		client, err := GetAwsElastiCacheClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -71,7 +127,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsElb:
-		client, err := GetAwsElbClient(context.TODO())
+		client, err := GetAwsElbClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -80,7 +136,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsFirehose:
-		client, err := GetAwsFirehoseClient(context.TODO())
+		client, err := GetAwsFirehoseClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -89,7 +145,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsKinesis:
-		client, err := GetAwsKinesisClient(context.TODO())
+		client, err := GetAwsKinesisClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -97,8 +153,17 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		m := BuildAwsKinesisTagsMapper(c, client)
 		return m, nil
 
+	case datadog.AwsLambda:
+		client, err := GetAwsLambdaClient(ctx, optFns...)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		m := BuildAwsLambdaTagsMapper(c, client)
+		return m, nil
+
 	case datadog.AwsOpenSearchService:
-		client, err := GetAwsOpenSearchServiceClient(context.TODO())
+		client, err := GetAwsOpenSearchServiceClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -107,7 +172,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsRds:
-		client, err := GetAwsRdsClient(context.TODO())
+		client, err := GetAwsRdsClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -116,7 +181,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsSns:
-		client, err := GetAwsSnsClient(context.TODO())
+		client, err := GetAwsSnsClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -125,7 +190,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsStepFunction:
-		client, err := GetAwsStepFunctionClient(context.TODO())
+		client, err := GetAwsStepFunctionClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -134,7 +199,7 @@ func BuildTagsMapper(it datadog.IntegrationTarget) (TagsMapper, error) {
 		return m, nil
 
 	case datadog.AwsSqs:
-		client, err := GetAwsSqsClient(context.TODO())
+		client, err := GetAwsSqsClient(ctx, optFns...)
 		if err != nil {
 			return nil, fmt.Errorf("%w", err)
 		}
@@ -0,0 +1,182 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// expiresLayout represents the layout of Suppression.Expires.
+const expiresLayout = "2006-01-02"
+
+// Baseline represents the accepted pairs of metric and resource which should not be reported as unmonitored.
+type Baseline struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// Suppression represents an accepted pair of metric and resource.
+// Expires is the last date of the suppression formatted as YYYY-MM-DD, and never expires when it is empty.
+type Suppression struct {
+	Metric   string `yaml:"metric"`
+	Resource string `yaml:"resource"`
+	Reason   string `yaml:"reason,omitempty"`
+	Expires  string `yaml:"expires,omitempty"`
+}
+
+// Expired returns whether the suppression is expired at now.
+func (s Suppression) Expired(now time.Time) bool {
+	if s.Expires == "" {
+		return false
+	}
+
+	expires, err := time.Parse(expiresLayout, s.Expires)
+	if err != nil {
+		return true
+	}
+
+	return !now.Before(expires.AddDate(0, 0, 1))
+}
+
+func (s Suppression) key() string {
+	return fmt.Sprintf("%s/%s", s.Metric, s.Resource)
+}
+
+// LoadBaseline reads the baseline file.
+// An empty Baseline is returned when the file does not exist and allowMissing is true.
+func LoadBaseline(path string, allowMissing bool) (*Baseline, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if allowMissing && errors.Is(err, fs.ErrNotExist) {
+			return &Baseline{}, nil
+		}
+		return nil, fmt.Errorf("failed to read baseline file: %w", err)
+	}
+
+	var baseline Baseline
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&baseline); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for i, s := range baseline.Suppressions {
+		if s.Metric == "" || s.Resource == "" {
+			return nil, fmt.Errorf("%s: suppression %d: both metric and resource are required", path, i)
+		}
+
+		if _, err := time.Parse(expiresLayout, s.Expires); s.Expires != "" && err != nil {
+			return nil, fmt.Errorf("%s: suppression %d: invalid expires %q, must be formatted as YYYY-MM-DD", path, i, s.Expires)
+		}
+	}
+
+	return &baseline, nil
+}
+
+// WriteBaseline writes the baseline as YAML.
+func WriteBaseline(w io.Writer, baseline *Baseline) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(baseline); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// GenerateBaseline generates the baseline accepting every unmonitored pair of the scan result.
+// The scan result is supposed not to be applied any baseline.
+// The pairs in the previous baseline are kept as they are, even when expired, not to be accepted silently again,
+// and the pairs which are not unmonitored anymore are dropped.
+// The pairs of the metrics which are not evaluated in the scan, e.g. of other integrations or failed evaluations, are kept.
+func GenerateBaseline(scan Scan, previous *Baseline) *Baseline {
+	evaluated := make(map[string]struct{}, len(scan.Metrics))
+	for _, m := range scan.Metrics {
+		evaluated[m.Metric] = struct{}{}
+	}
+
+	baseline := &Baseline{Suppressions: make([]Suppression, 0)}
+	kept := make(map[string]Suppression)
+	if previous != nil {
+		for _, s := range previous.Suppressions {
+			if _, ok := evaluated[s.Metric]; !ok {
+				baseline.Suppressions = append(baseline.Suppressions, s)
+				continue
+			}
+			kept[s.key()] = s
+		}
+	}
+
+	for _, m := range scan.Metrics {
+		for _, id := range m.Unmonitored {
+			s := Suppression{Metric: m.Metric, Resource: id}
+			if k, ok := kept[s.key()]; ok {
+				s = k
+			}
+			baseline.Suppressions = append(baseline.Suppressions, s)
+		}
+	}
+
+	sortSuppressions(baseline.Suppressions)
+
+	return baseline
+}
+
+// ApplyBaseline moves the unmonitored pairs suppressed by the baseline into Excluded so that only new gaps are reported.
+// Expired suppressions are not applied, and recorded in ExpiredSuppressions.
+func (s *Scan) ApplyBaseline(baseline *Baseline, now time.Time) {
+	active := make(map[string]Suppression)
+	for _, sup := range baseline.Suppressions {
+		if sup.Expired(now) {
+			s.ExpiredSuppressions = append(s.ExpiredSuppressions, sup)
+			continue
+		}
+		active[sup.key()] = sup
+	}
+
+	for i := 0; i < len(s.Metrics); i++ {
+		m := &s.Metrics[i]
+
+		unmonitored := make([]string, 0, len(m.Unmonitored))
+		suppressed := make(map[string]struct{})
+		for _, id := range m.Unmonitored {
+			sup, ok := active[Suppression{Metric: m.Metric, Resource: id}.key()]
+			if !ok {
+				unmonitored = append(unmonitored, id)
+				continue
+			}
+
+			suppressed[id] = struct{}{}
+			s.Suppressed = append(s.Suppressed, sup)
+			m.Excluded = append(m.Excluded, id)
+		}
+		m.Unmonitored = unmonitored
+
+		violations := make([]string, 0, len(m.Violations))
+		for _, id := range m.Violations {
+			if _, ok := suppressed[id]; !ok {
+				violations = append(violations, id)
+			}
+		}
+		m.Violations = violations
+	}
+
+	sortSuppressions(s.Suppressed)
+	sortSuppressions(s.ExpiredSuppressions)
+}
+
+func sortSuppressions(suppressions []Suppression) {
+	sort.Slice(suppressions, func(i, j int) bool {
+		return suppressions[i].key() < suppressions[j].key()
+	})
+}
@@ -0,0 +1,346 @@
+package report_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_SuppressionExpired(t *testing.T) {
+	now := time.Date(2022, 3, 10, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		expires  string
+		expected bool
+	}{
+		{name: "when expires is empty", expires: "", expected: false},
+		{name: "when expires is in the future", expires: "2022-03-11", expected: false},
+		{name: "when expires is today", expires: "2022-03-10", expected: false},
+		{name: "when expires is in the past", expires: "2022-03-09", expected: true},
+	}
+
+	for _, c := range cases {
+		actual := report.Suppression{Metric: "m", Resource: "r", Expires: c.expires}.Expired(now)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_ApplyBaseline(t *testing.T) {
+	now := time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		scan     report.Scan
+		baseline *report.Baseline
+		expected report.Scan
+	}{
+		{
+			name: "when some suppressions are active and expired",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1", "db-2"},
+							Excluded:    []string{"db-3"},
+							Violations:  []string{"db-1"},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+						},
+					},
+				},
+			},
+			baseline: &report.Baseline{
+				Suppressions: []report.Suppression{
+					{Metric: "aws.rds.free_storage_space", Resource: "db-1", Reason: "migrating"},
+					{Metric: "aws.rds.cpuutilization", Resource: "db-2", Expires: "2022-03-01"},
+					{Metric: "aws.rds.cpuutilization", Resource: "db-9"},
+				},
+			},
+			expected: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{"db-3", "db-1"},
+							Violations:  []string{},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+							Violations:  []string{},
+						},
+					},
+				},
+				Suppressed: []report.Suppression{
+					{Metric: "aws.rds.free_storage_space", Resource: "db-1", Reason: "migrating"},
+				},
+				ExpiredSuppressions: []report.Suppression{
+					{Metric: "aws.rds.cpuutilization", Resource: "db-2", Expires: "2022-03-01"},
+				},
+			},
+		},
+		{
+			name: "when baseline is empty",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+						},
+					},
+				},
+			},
+			baseline: &report.Baseline{},
+			expected: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+							Violations:  []string{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.scan.ApplyBaseline(c.baseline, now)
+		if !assert.Equal(t, c.expected, c.scan) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, c.scan)
+		}
+	}
+}
+
+func Test_GenerateBaseline(t *testing.T) {
+	scan := report.Scan{
+		Metrics: []report.MetricResult{
+			{
+				Metric:      "aws.sqs.number_of_messages_sent",
+				Integration: datadog.AwsSqs,
+				Result: evaluator.Result{
+					Resources:   []string{"queue-1"},
+					Unmonitored: []string{},
+				},
+			},
+			{
+				Metric:      "aws.rds.free_storage_space",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2", "db-3"},
+					Unmonitored: []string{"db-1", "db-2"},
+				},
+			},
+			{
+				Metric:      "aws.rds.cpuutilization",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2", "db-3"},
+					Unmonitored: []string{"db-2"},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		previous *report.Baseline
+		expected *report.Baseline
+	}{
+		{
+			name: "when previous baseline has active, expired and resolved suppressions",
+			previous: &report.Baseline{
+				Suppressions: []report.Suppression{
+					{Metric: "aws.rds.free_storage_space", Resource: "db-1", Reason: "migrating", Expires: "2022-04-01"},
+					{Metric: "aws.rds.cpuutilization", Resource: "db-2", Reason: "expired", Expires: "2022-03-01"},
+					{Metric: "aws.rds.cpuutilization", Resource: "db-1", Reason: "resolved"},
+				},
+			},
+			expected: &report.Baseline{
+				Suppressions: []report.Suppression{
+					{Metric: "aws.rds.cpuutilization", Resource: "db-2", Reason: "expired", Expires: "2022-03-01"},
+					{Metric: "aws.rds.free_storage_space", Resource: "db-1", Reason: "migrating", Expires: "2022-04-01"},
+					{Metric: "aws.rds.free_storage_space", Resource: "db-2"},
+				},
+			},
+		},
+		{
+			name: "when previous baseline has suppressions of metrics not evaluated",
+			previous: &report.Baseline{
+				Suppressions: []report.Suppression{
+					{Metric: "aws.sqs.approximate_age_of_oldest_message", Resource: "queue-1", Reason: "sandbox"},
+					{Metric: "aws.sqs.number_of_messages_sent", Resource: "queue-1", Reason: "resolved"},
+				},
+			},
+			expected: &report.Baseline{
+				Suppressions: []report.Suppression{
+					{Metric: "aws.rds.cpuutilization", Resource: "db-2"},
+					{Metric: "aws.rds.free_storage_space", Resource: "db-1"},
+					{Metric: "aws.rds.free_storage_space", Resource: "db-2"},
+					{Metric: "aws.sqs.approximate_age_of_oldest_message", Resource: "queue-1", Reason: "sandbox"},
+				},
+			},
+		},
+		{
+			name:     "when previous baseline does not exist",
+			previous: nil,
+			expected: &report.Baseline{
+				Suppressions: []report.Suppression{
+					{Metric: "aws.rds.cpuutilization", Resource: "db-2"},
+					{Metric: "aws.rds.free_storage_space", Resource: "db-1"},
+					{Metric: "aws.rds.free_storage_space", Resource: "db-2"},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := report.GenerateBaseline(scan, c.previous)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_LoadBaseline(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		name     string
+		content  string
+		expected *report.Baseline
+		isErr    bool
+	}{
+		{
+			name: "when baseline is valid",
+			content: `suppressions:
+  - metric: aws.rds.cpuutilization
+    resource: db-1
+    reason: decommissioning
+    expires: "2022-04-01"
+`,
+			expected: &report.Baseline{
+				Suppressions: []report.Suppression{
+					{Metric: "aws.rds.cpuutilization", Resource: "db-1", Reason: "decommissioning", Expires: "2022-04-01"},
+				},
+			},
+		},
+		{
+			name:     "when baseline is empty",
+			content:  "",
+			expected: &report.Baseline{},
+		},
+		{
+			name: "when resource is missing",
+			content: `suppressions:
+  - metric: aws.rds.cpuutilization
+`,
+			isErr: true,
+		},
+		{
+			name: "when expires is invalid",
+			content: `suppressions:
+  - metric: aws.rds.cpuutilization
+    resource: db-1
+    expires: 2022/04/01
+`,
+			isErr: true,
+		},
+		{
+			name: "when unknown field exists",
+			content: `suppressions:
+  - metric: aws.rds.cpuutilization
+    resource: db-1
+    until: "2022-04-01"
+`,
+			isErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		path := filepath.Join(dir, "baseline"+string(rune('a'+i))+".yaml")
+		if err := os.WriteFile(path, []byte(c.content), 0o600); err != nil {
+			t.Fatalf("failed to write baseline: %+v\n", err)
+		}
+
+		actual, err := report.LoadBaseline(path, false)
+		if c.isErr {
+			if !assert.NotNil(t, err) {
+				t.Errorf("case: %s is failed, expected error\n", c.name)
+			}
+			continue
+		}
+
+		if !assert.Nil(t, err) || !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v, err: %+v\n", c.name, c.expected, actual, err)
+		}
+	}
+}
+
+func Test_LoadBaselineMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.yaml")
+
+	actual, err := report.LoadBaseline(path, true)
+	assert.Nil(t, err)
+	assert.Equal(t, &report.Baseline{}, actual)
+
+	_, err = report.LoadBaseline(path, false)
+	assert.NotNil(t, err)
+}
+
+func Test_WriteBaseline(t *testing.T) {
+	expected := `suppressions:
+  - metric: aws.rds.cpuutilization
+    resource: db-1
+    reason: decommissioning
+  - metric: aws.rds.cpuutilization
+    resource: db-2
+    expires: "2022-04-01"
+`
+	baseline := &report.Baseline{
+		Suppressions: []report.Suppression{
+			{Metric: "aws.rds.cpuutilization", Resource: "db-1", Reason: "decommissioning"},
+			{Metric: "aws.rds.cpuutilization", Resource: "db-2", Expires: "2022-04-01"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteBaseline(&buf, baseline); err != nil {
+		t.Fatalf("failed to write baseline: %+v\n", err)
+	}
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"io"
+	"sort"
+)
+
+// Status represents whether a resource is monitored for a metric.
+type Status string
+
+const (
+	// StatusMonitored represents that the resource is covered by any monitor.
+	StatusMonitored Status = "monitored"
+	// StatusUnmonitored represents that the resource is covered by no monitor.
+	StatusUnmonitored Status = "unmonitored"
+	// StatusExcluded represents that the resource is ignored or explicitly excluded from the monitors.
+	StatusExcluded Status = "excluded"
+)
+
+// CoverageMatrix represents resources × metrics monitoring status per integration.
+type CoverageMatrix struct {
+	Integrations []IntegrationCoverage
+}
+
+// IntegrationCoverage represents the monitoring status of the integration resources.
+// Coverage is the percentage of monitored pairs of resource and metric, excluded ones are not counted.
+type IntegrationCoverage struct {
+	Integration string
+	Metrics     []string
+	Resources   []ResourceCoverage
+	Coverage    float64
+}
+
+// ResourceCoverage represents the monitoring status of a resource per metric.
+type ResourceCoverage struct {
+	ID       string
+	Metrics  map[string]Status
+	Coverage float64
+}
+
+// BuildCoverageMatrix builds CoverageMatrix from the scan result.
+func BuildCoverageMatrix(scan Scan) CoverageMatrix {
+	groups := groupByIntegration(scan)
+	matrix := CoverageMatrix{
+		Integrations: make([]IntegrationCoverage, 0, len(groups)),
+	}
+	for _, group := range groups {
+		matrix.Integrations = append(matrix.Integrations, buildIntegrationCoverage(group.Integration, group.Metrics))
+	}
+
+	return matrix
+}
+
+func buildIntegrationCoverage(it string, metrics []MetricResult) IntegrationCoverage {
+	ic := IntegrationCoverage{
+		Integration: it,
+		Metrics:     make([]string, 0, len(metrics)),
+	}
+
+	resources := make(map[string]map[string]Status)
+	for _, m := range metrics {
+		ic.Metrics = append(ic.Metrics, m.Metric)
+
+		for id, status := range resourceStatuses(m) {
+			if _, ok := resources[id]; !ok {
+				resources[id] = make(map[string]Status)
+			}
+			resources[id][m.Metric] = status
+		}
+	}
+	sort.Strings(ic.Metrics)
+
+	ids := make([]string, 0, len(resources))
+	for id := range resources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var monitored, total int
+	ic.Resources = make([]ResourceCoverage, 0, len(ids))
+	for _, id := range ids {
+		m, t := countStatuses(resources[id])
+		monitored += m
+		total += t
+
+		ic.Resources = append(ic.Resources, ResourceCoverage{
+			ID:       id,
+			Metrics:  resources[id],
+			Coverage: percentage(m, t),
+		})
+	}
+	ic.Coverage = percentage(monitored, total)
+
+	return ic
+}
+
+// resourceStatuses returns the status of each resource for the metric.
+func resourceStatuses(m MetricResult) map[string]Status {
+	excluded := make(map[string]struct{}, len(m.Excluded))
+	for _, id := range m.Excluded {
+		excluded[id] = struct{}{}
+	}
+
+	statuses := make(map[string]Status, len(m.Resources))
+	for _, id := range m.Resources {
+		status := StatusUnmonitored
+		if _, ok := excluded[id]; ok {
+			status = StatusExcluded
+		} else if _, ok := m.Monitored[id]; ok {
+			status = StatusMonitored
+		}
+		statuses[id] = status
+	}
+
+	return statuses
+}
+
+func countStatuses(statuses map[string]Status) (monitored, total int) {
+	for _, status := range statuses {
+		switch status {
+		case StatusMonitored:
+			monitored++
+			total++
+		case StatusUnmonitored:
+			total++
+		case StatusExcluded:
+		}
+	}
+
+	return monitored, total
+}
+
+// percentage returns 100 when there is nothing to be monitored.
+func percentage(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return float64(n) * 100 / float64(total)
+}
+
+// WriteCoverage writes CoverageMatrix as JSON.
+func WriteCoverage(w io.Writer, scan Scan, pretty bool) error {
+	return WriteJSONValue(w, BuildCoverageMatrix(scan), pretty)
+}
@@ -0,0 +1,95 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_BuildCoverageMatrix(t *testing.T) {
+	monitor := datadog.MonitorRef{ID: 1, Name: "monitor"}
+
+	scan := report.Scan{
+		Metrics: []report.MetricResult{
+			{
+				Metric:      "aws.rds.cpuutilization",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2", "db-3"},
+					Unmonitored: []string{"db-2"},
+					Excluded:    []string{"db-3"},
+					Monitored:   map[string][]datadog.MonitorRef{"db-1": {monitor}},
+				},
+			},
+			{
+				Metric:      "aws.rds.free_storage_space",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2", "db-3"},
+					Unmonitored: []string{"db-1", "db-2", "db-3"},
+					Excluded:    []string{},
+					Monitored:   map[string][]datadog.MonitorRef{},
+				},
+			},
+			{
+				Metric:      "aws.sqs.number_of_messages_sent",
+				Integration: datadog.AwsSqs,
+				Result: evaluator.Result{
+					Resources:   []string{},
+					Unmonitored: []string{},
+					Excluded:    []string{},
+					Monitored:   map[string][]datadog.MonitorRef{},
+				},
+			},
+		},
+	}
+
+	expected := report.CoverageMatrix{
+		Integrations: []report.IntegrationCoverage{
+			{
+				Integration: "aws_rds",
+				Metrics:     []string{"aws.rds.cpuutilization", "aws.rds.free_storage_space"},
+				Resources: []report.ResourceCoverage{
+					{
+						ID: "db-1",
+						Metrics: map[string]report.Status{
+							"aws.rds.cpuutilization":     report.StatusMonitored,
+							"aws.rds.free_storage_space": report.StatusUnmonitored,
+						},
+						Coverage: 50,
+					},
+					{
+						ID: "db-2",
+						Metrics: map[string]report.Status{
+							"aws.rds.cpuutilization":     report.StatusUnmonitored,
+							"aws.rds.free_storage_space": report.StatusUnmonitored,
+						},
+						Coverage: 0,
+					},
+					{
+						ID: "db-3",
+						Metrics: map[string]report.Status{
+							"aws.rds.cpuutilization":     report.StatusExcluded,
+							"aws.rds.free_storage_space": report.StatusUnmonitored,
+						},
+						Coverage: 0,
+					},
+				},
+				Coverage: 20,
+			},
+			{
+				Integration: "aws_sqs",
+				Metrics:     []string{"aws.sqs.number_of_messages_sent"},
+				Resources:   []report.ResourceCoverage{},
+				Coverage:    100,
+			},
+		},
+	}
+
+	actual := report.BuildCoverageMatrix(scan)
+	assert.Equal(t, expected, actual)
+}
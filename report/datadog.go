@@ -0,0 +1,184 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	dd "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+
+	"github.com/terakoya76/modd/datadog"
+)
+
+// UnmonitoredCountMetric represents the Datadog custom metric submitted per pair of metric and resource.
+const UnmonitoredCountMetric = "modd.unmonitored.count"
+
+const (
+	// seriesBatchSize represents the number of series submitted at once.
+	seriesBatchSize = 1000
+	// maxEventTextLength represents the limit of Datadog event text.
+	maxEventTextLength = 4000
+)
+
+// BuildUnmonitoredSeries returns the gauges of every pair of metric and resource tagged by integration/metric/resource.
+// The gauge is 1 when the resource is unmonitored and 0 otherwise, so that remediated pairs are not left behind.
+func BuildUnmonitoredSeries(scan Scan, now time.Time) []dd.Series {
+	ts := float64(now.Unix())
+	series := make([]dd.Series, 0)
+	for _, m := range scan.Metrics {
+		unmonitored := make(map[string]struct{}, len(m.Unmonitored))
+		for _, id := range m.Unmonitored {
+			unmonitored[id] = struct{}{}
+		}
+
+		for _, id := range m.Resources {
+			v := 0.0
+			if _, ok := unmonitored[id]; ok {
+				v = 1
+			}
+
+			s := dd.NewSeries(UnmonitoredCountMetric, [][]*float64{{dd.PtrFloat64(ts), dd.PtrFloat64(v)}})
+			s.SetType("gauge")
+			s.Tags = []string{
+				fmt.Sprintf("integration:%s", m.Integration),
+				fmt.Sprintf("metric:%s", m.Metric),
+				fmt.Sprintf("resource:%s", id),
+			}
+			series = append(series, *s)
+		}
+	}
+
+	return series
+}
+
+// BuildGapsEvent returns the Datadog event summarizing the unmonitored pairs newly found since the previous scan result.
+// Only the metrics evaluated in both scan results are compared, not to report every pair of a metric whose evaluation
+// failed or was skipped in the previous scan as new.
+func BuildGapsEvent(scan, previous Scan) dd.EventCreateRequest {
+	evaluated := make(map[string]struct{}, len(previous.Metrics))
+	for _, m := range previous.Metrics {
+		evaluated[m.Metric] = struct{}{}
+	}
+
+	gs := newGapSummary()
+	for _, d := range CompareScans(previous, scan).Metrics {
+		if _, ok := evaluated[d.Metric]; ok {
+			gs.add(d.Integration, d.Metric, d.AddedUnmonitored)
+		}
+	}
+
+	if gs.gaps == 0 {
+		return gs.event("modd: no new unmonitored resources", "No resource became unmonitored since the previous scan.\n")
+	}
+
+	return gs.event(fmt.Sprintf("modd: %d new unmonitored resources", gs.gaps), gs.text.String())
+}
+
+// BuildSummaryEvent returns the Datadog event summarizing every unmonitored pair of the scan result.
+// It is posted instead of BuildGapsEvent when no previous scan result exists, e.g. on the first scan.
+func BuildSummaryEvent(scan Scan) dd.EventCreateRequest {
+	gs := newGapSummary()
+	for _, m := range scan.Metrics {
+		gs.add(m.Integration, m.Metric, m.Unmonitored)
+	}
+
+	if gs.gaps == 0 {
+		return gs.event("modd: no unmonitored resources", "No resource is unmonitored. New gaps are reported from the next scan.\n")
+	}
+
+	text := "No previous scan is found, so every unmonitored resource is listed. New gaps are reported from the next scan.\n"
+	return gs.event(fmt.Sprintf("modd: %d unmonitored resources", gs.gaps), text+gs.text.String())
+}
+
+// gapSummary accumulates the unmonitored pairs per metric into the text and the tags of an event.
+type gapSummary struct {
+	gaps         int
+	tags         []string
+	integrations map[datadog.IntegrationTarget]struct{}
+	text         strings.Builder
+}
+
+func newGapSummary() *gapSummary {
+	return &gapSummary{
+		tags:         []string{"source:modd"},
+		integrations: make(map[datadog.IntegrationTarget]struct{}),
+	}
+}
+
+func (gs *gapSummary) add(it datadog.IntegrationTarget, metric string, unmonitored []string) {
+	if len(unmonitored) == 0 {
+		return
+	}
+
+	gs.gaps += len(unmonitored)
+	if _, ok := gs.integrations[it]; !ok {
+		gs.integrations[it] = struct{}{}
+		gs.tags = append(gs.tags, fmt.Sprintf("integration:%s", it))
+	}
+	fmt.Fprintf(&gs.text, "- %s %s: %s\n", it, metric, strings.Join(unmonitored, ", "))
+}
+
+// event returns the event which is a warning when any gap exists.
+func (gs *gapSummary) event(title, text string) dd.EventCreateRequest {
+	alertType := dd.EVENTALERTTYPE_SUCCESS
+	if gs.gaps > 0 {
+		alertType = dd.EVENTALERTTYPE_WARNING
+	}
+
+	event := dd.NewEventCreateRequest(truncateEventText(text), title)
+	event.SetAlertType(alertType)
+	event.SetSourceTypeName("modd")
+	event.SetAggregationKey("modd")
+	event.Tags = gs.tags
+
+	return *event
+}
+
+// truncateEventText truncates the text to the limit of Datadog event text at the end of the last line which fits in.
+// A line longer than the limit is truncated at a rune boundary.
+func truncateEventText(text string) string {
+	const ellipsis = "...\n"
+	if len(text) <= maxEventTextLength {
+		return text
+	}
+
+	limit := maxEventTextLength - len(ellipsis)
+	if i := strings.LastIndex(text[:limit], "\n"); i >= 0 {
+		return text[:i+1] + ellipsis
+	}
+
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+
+	return text[:limit] + ellipsis
+}
+
+// PublishDatadog submits the gauges of unmonitored resources, and posts the event summarizing the new gaps.
+// The event summarizes every gap instead when the previous scan result is not given.
+func PublishDatadog(ctx context.Context, client *dd.APIClient, scan Scan, previous *Scan, now time.Time) error {
+	series := BuildUnmonitoredSeries(scan, now)
+	for start := 0; start < len(series); start += seriesBatchSize {
+		end := start + seriesBatchSize
+		if end > len(series) {
+			end = len(series)
+		}
+
+		if _, _, err := client.MetricsApi.SubmitMetrics(ctx, *dd.NewMetricsPayload(series[start:end])); err != nil {
+			return fmt.Errorf("failed to submit metrics: %w", err)
+		}
+	}
+
+	event := BuildSummaryEvent(scan)
+	if previous != nil {
+		event = BuildGapsEvent(scan, *previous)
+	}
+
+	if _, _, err := client.EventsApi.CreateEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to post event: %w", err)
+	}
+
+	return nil
+}
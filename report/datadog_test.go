@@ -0,0 +1,474 @@
+package report_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	dd "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_BuildUnmonitoredSeries(t *testing.T) {
+	now := time.Unix(1646092800, 0)
+	cases := []struct {
+		name     string
+		scan     report.Scan
+		expected map[string]float64
+	}{
+		{
+			name: "when some resources are unmonitored",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+						},
+					},
+				},
+			},
+			expected: map[string]float64{
+				"integration:aws_sqs,metric:aws.sqs.number_of_messages_sent,resource:queue-1": 0,
+				"integration:aws_rds,metric:aws.rds.cpuutilization,resource:db-1":             0,
+				"integration:aws_rds,metric:aws.rds.cpuutilization,resource:db-2":             1,
+				"integration:aws_rds,metric:aws.rds.cpuutilization,resource:db-3":             0,
+			},
+		},
+		{
+			name:     "when no metric is evaluated",
+			scan:     report.Scan{Metrics: []report.MetricResult{}},
+			expected: map[string]float64{},
+		},
+	}
+
+	for _, c := range cases {
+		series := report.BuildUnmonitoredSeries(c.scan, now)
+
+		actual := make(map[string]float64, len(series))
+		for _, s := range series {
+			assert.Equal(t, report.UnmonitoredCountMetric, s.Metric)
+			assert.Equal(t, "gauge", s.GetType())
+			assert.Equal(t, float64(now.Unix()), *s.Points[0][0])
+			actual[strings.Join(s.Tags, ",")] = *s.Points[0][1]
+		}
+
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_BuildGapsEvent(t *testing.T) {
+	scan := report.Scan{
+		Metrics: []report.MetricResult{
+			{
+				Metric:      "aws.rds.free_storage_space",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2", "db-3"},
+					Unmonitored: []string{"db-1", "db-2"},
+				},
+			},
+			{
+				Metric:      "aws.rds.cpuutilization",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2", "db-3"},
+					Unmonitored: []string{"db-2"},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		previous  report.Scan
+		title     string
+		text      string
+		alertType dd.EventAlertType
+		tags      []string
+	}{
+		{
+			name: "when the metric is not evaluated in previous",
+			previous: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{},
+						},
+					},
+				},
+			},
+			title:     "modd: 1 new unmonitored resources",
+			text:      "- aws_rds aws.rds.cpuutilization: db-2\n",
+			alertType: dd.EVENTALERTTYPE_WARNING,
+			tags:      []string{"source:modd", "integration:aws_rds"},
+		},
+		{
+			name: "when some gaps exist in previous",
+			previous: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1"},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+						},
+					},
+				},
+			},
+			title:     "modd: 1 new unmonitored resources",
+			text:      "- aws_rds aws.rds.free_storage_space: db-2\n",
+			alertType: dd.EVENTALERTTYPE_WARNING,
+			tags:      []string{"source:modd", "integration:aws_rds"},
+		},
+		{
+			name: "when no new gap exists",
+			previous: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1", "db-2"},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+						},
+					},
+				},
+			},
+			title:     "modd: no new unmonitored resources",
+			text:      "No resource became unmonitored since the previous scan.\n",
+			alertType: dd.EVENTALERTTYPE_SUCCESS,
+			tags:      []string{"source:modd"},
+		},
+	}
+
+	for _, c := range cases {
+		event := report.BuildGapsEvent(scan, c.previous)
+		if !assert.Equal(t, c.title, event.Title) ||
+			!assert.Equal(t, c.text, event.Text) ||
+			!assert.Equal(t, c.alertType, event.GetAlertType()) ||
+			!assert.Equal(t, c.tags, event.Tags) {
+			t.Errorf("case: %s is failed, actual: %+v\n", c.name, event)
+		}
+	}
+}
+
+func Test_BuildSummaryEvent(t *testing.T) {
+	cases := []struct {
+		name      string
+		scan      report.Scan
+		title     string
+		text      string
+		alertType dd.EventAlertType
+		tags      []string
+	}{
+		{
+			name: "when some gaps exist",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1", "db-2"},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{},
+						},
+					},
+				},
+			},
+			title: "modd: 2 unmonitored resources",
+			text: "No previous scan is found, so every unmonitored resource is listed. New gaps are reported from the next scan.\n" +
+				"- aws_rds aws.rds.free_storage_space: db-1, db-2\n",
+			alertType: dd.EVENTALERTTYPE_WARNING,
+			tags:      []string{"source:modd", "integration:aws_rds"},
+		},
+		{
+			name: "when no gap exists",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{},
+						},
+					},
+				},
+			},
+			title:     "modd: no unmonitored resources",
+			text:      "No resource is unmonitored. New gaps are reported from the next scan.\n",
+			alertType: dd.EVENTALERTTYPE_SUCCESS,
+			tags:      []string{"source:modd"},
+		},
+	}
+
+	for _, c := range cases {
+		event := report.BuildSummaryEvent(c.scan)
+		if !assert.Equal(t, c.title, event.Title) ||
+			!assert.Equal(t, c.text, event.Text) ||
+			!assert.Equal(t, c.alertType, event.GetAlertType()) ||
+			!assert.Equal(t, c.tags, event.Tags) {
+			t.Errorf("case: %s is failed, actual: %+v\n", c.name, event)
+		}
+	}
+}
+
+func Test_BuildGapsEventTruncated(t *testing.T) {
+	resources := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		resources = append(resources, fmt.Sprintf("データベース-%d", i))
+	}
+
+	cases := []struct {
+		name        string
+		unmonitored map[string][]string
+		prefix      string
+		lines       int
+	}{
+		{
+			name:        "when the first line exceeds the limit",
+			unmonitored: map[string][]string{"aws.rds.cpuutilization": resources},
+			prefix:      "- aws_rds aws.rds.cpuutilization: データベース-0, ",
+			lines:       1,
+		},
+		{
+			name: "when a following line exceeds the limit",
+			unmonitored: map[string][]string{
+				"aws.rds.cpuutilization":     resources[:1],
+				"aws.rds.free_storage_space": resources,
+			},
+			prefix: "- aws_rds aws.rds.cpuutilization: データベース-0\n",
+			lines:  2,
+		},
+	}
+
+	for _, c := range cases {
+		var scan, previous report.Scan
+		for metric, unmonitored := range c.unmonitored {
+			scan.Metrics = append(scan.Metrics, report.MetricResult{
+				Metric:      metric,
+				Integration: datadog.AwsRds,
+				Result:      evaluator.Result{Resources: resources, Unmonitored: unmonitored},
+			})
+			previous.Metrics = append(previous.Metrics, report.MetricResult{
+				Metric:      metric,
+				Integration: datadog.AwsRds,
+				Result:      evaluator.Result{Resources: resources, Unmonitored: []string{}},
+			})
+		}
+
+		text := report.BuildGapsEvent(scan, previous).Text
+		if !assert.LessOrEqual(t, len(text), 4000) ||
+			!assert.True(t, utf8.ValidString(text)) ||
+			!assert.True(t, strings.HasPrefix(text, c.prefix)) ||
+			!assert.True(t, strings.HasSuffix(text, "...\n")) ||
+			!assert.Equal(t, c.lines, strings.Count(text, "\n")) {
+			t.Errorf("case: %s is failed, actual: %+v\n", c.name, text)
+		}
+	}
+}
+
+// newTestDatadogContext returns the context to send requests to the stand-in server.
+func newTestDatadogContext(t *testing.T, serverURL string) context.Context {
+	t.Helper()
+
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("failed to parse server url: %+v\n", err)
+	}
+
+	ctx := context.WithValue(context.Background(), dd.ContextAPIKeys, map[string]dd.APIKey{
+		"apiKeyAuth": {Key: "api-key"},
+		"appKeyAuth": {Key: "app-key"},
+	})
+	ctx = context.WithValue(ctx, dd.ContextServerIndex, 1)
+	ctx = context.WithValue(ctx, dd.ContextServerVariables, map[string]string{
+		"protocol": u.Scheme,
+		"name":     u.Host,
+	})
+
+	return ctx
+}
+
+func Test_PublishDatadog(t *testing.T) {
+	var mu sync.Mutex
+	series := make([]dd.Series, 0)
+	events := make([]dd.EventCreateRequest, 0)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, "api-key", r.Header.Get("DD-API-KEY"))
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read body: %+v\n", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/series":
+			var payload dd.MetricsPayload
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Errorf("failed to decode series: %+v\n", err)
+			}
+			series = append(series, payload.Series...)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		case "/api/v1/events":
+			var event dd.EventCreateRequest
+			if err := json.Unmarshal(body, &event); err != nil {
+				t.Errorf("failed to decode event: %+v\n", err)
+			}
+			events = append(events, event)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":["not found"]}`))
+		}
+	}))
+	defer ts.Close()
+
+	scan := report.Scan{
+		Metrics: []report.MetricResult{
+			{
+				Metric:      "aws.rds.cpuutilization",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2", "db-3"},
+					Unmonitored: []string{"db-2"},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		previous *report.Scan
+		events   []string
+	}{
+		{
+			name:     "when previous is nil",
+			previous: nil,
+			events:   []string{"modd: 1 unmonitored resources"},
+		},
+		{
+			name: "when previous is given",
+			previous: &report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{},
+						},
+					},
+				},
+			},
+			events: []string{"modd: 1 new unmonitored resources"},
+		},
+	}
+
+	ctx := newTestDatadogContext(t, ts.URL)
+	for _, c := range cases {
+		mu.Lock()
+		series = make([]dd.Series, 0)
+		events = make([]dd.EventCreateRequest, 0)
+		mu.Unlock()
+
+		err := report.PublishDatadog(ctx, dd.NewAPIClient(dd.NewConfiguration()), scan, c.previous, time.Now())
+		if !assert.Nil(t, err) {
+			t.Fatalf("case: %s is failed, err: %+v\n", c.name, err)
+		}
+
+		titles := make([]string, 0, len(events))
+		for _, e := range events {
+			titles = append(titles, e.Title)
+		}
+		if !assert.Len(t, series, 3) || !assert.Equal(t, c.events, titles) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.events, titles)
+		}
+	}
+}
+
+func Test_PublishDatadogError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["Forbidden"]}`))
+	}))
+	defer ts.Close()
+
+	scan := report.Scan{
+		Metrics: []report.MetricResult{
+			{
+				Metric:      "aws.rds.cpuutilization",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2", "db-3"},
+					Unmonitored: []string{"db-2"},
+				},
+			},
+		},
+	}
+
+	ctx := newTestDatadogContext(t, ts.URL)
+	err := report.PublishDatadog(ctx, dd.NewAPIClient(dd.NewConfiguration()), scan, nil, time.Now())
+	assert.NotNil(t, err)
+}
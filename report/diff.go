@@ -0,0 +1,235 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/filter"
+)
+
+// Diff represents the changes between two scan results.
+// Metrics holds only the metrics which have any change.
+type Diff struct {
+	Metrics            []MetricDiff
+	AddedUnsupported   []string
+	RemovedUnsupported []string
+}
+
+// MetricDiff represents the changes of a metric between two scan results.
+// AddedUnmonitored holds the resources newly unmonitored, RemovedUnmonitored holds the ones not unmonitored anymore,
+// e.g. covered, excluded or deleted, and AddedMonitored/RemovedMonitored hold the ones newly covered or not covered anymore.
+type MetricDiff struct {
+	Metric             string
+	Integration        datadog.IntegrationTarget
+	AddedUnmonitored   []string
+	RemovedUnmonitored []string
+	AddedMonitored     []string
+	RemovedMonitored   []string
+}
+
+func (d MetricDiff) empty() bool {
+	return len(d.AddedUnmonitored) == 0 && len(d.RemovedUnmonitored) == 0 &&
+		len(d.AddedMonitored) == 0 && len(d.RemovedMonitored) == 0
+}
+
+// CompareScans returns the changes from the old scan result to the new one.
+// A metric which exists only in one of them is compared with an empty result.
+func CompareScans(oldScan, newScan Scan) Diff {
+	olds := make(map[string]MetricResult, len(oldScan.Metrics))
+	for _, m := range oldScan.Metrics {
+		olds[m.Metric] = m
+	}
+
+	news := make(map[string]MetricResult, len(newScan.Metrics))
+	for _, m := range newScan.Metrics {
+		news[m.Metric] = m
+	}
+
+	metrics := make([]string, 0, len(olds)+len(news))
+	for metric := range olds {
+		metrics = append(metrics, metric)
+	}
+	for metric := range news {
+		if _, ok := olds[metric]; !ok {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	diff := Diff{
+		Metrics:            make([]MetricDiff, 0),
+		AddedUnsupported:   sortedDifference(newScan.Unsupported, oldScan.Unsupported),
+		RemovedUnsupported: sortedDifference(oldScan.Unsupported, newScan.Unsupported),
+	}
+
+	for _, metric := range metrics {
+		o, n := olds[metric], news[metric]
+		it := n.Integration
+		if it == "" {
+			it = o.Integration
+		}
+
+		oldMonitored, newMonitored := monitoredIdentifiers(o), monitoredIdentifiers(n)
+		md := MetricDiff{
+			Metric:             metric,
+			Integration:        it,
+			AddedUnmonitored:   sortedDifference(n.Unmonitored, o.Unmonitored),
+			RemovedUnmonitored: sortedDifference(o.Unmonitored, n.Unmonitored),
+			AddedMonitored:     sortedDifference(newMonitored, oldMonitored),
+			RemovedMonitored:   sortedDifference(oldMonitored, newMonitored),
+		}
+		if !md.empty() {
+			diff.Metrics = append(diff.Metrics, md)
+		}
+	}
+
+	sort.Slice(diff.Metrics, func(i, j int) bool {
+		if diff.Metrics[i].Integration != diff.Metrics[j].Integration {
+			return diff.Metrics[i].Integration < diff.Metrics[j].Integration
+		}
+		return diff.Metrics[i].Metric < diff.Metrics[j].Metric
+	})
+
+	return diff
+}
+
+// Empty returns whether nothing is changed.
+func (d Diff) Empty() bool {
+	return len(d.Metrics) == 0 && len(d.AddedUnsupported) == 0 && len(d.RemovedUnsupported) == 0
+}
+
+// WriteDiff writes the changes between two scan results in the format.
+// Only json, table and markdown formats are supported.
+func WriteDiff(w io.Writer, diff Diff, format string, pretty bool) error {
+	switch format {
+	case config.JSONFormat:
+		return WriteJSONValue(w, diff, pretty)
+	case config.TableFormat:
+		return writeDiffTable(w, diff)
+	case config.MarkdownFormat:
+		return writeDiffMarkdown(w, diff)
+	default:
+		return fmt.Errorf("unsupported output format of diff %q, must be one of json, table or markdown", format)
+	}
+}
+
+// diffChanges returns the changes as pairs of a sign and a resource,
+// where `+` means added and `-` means removed.
+func diffChanges(added, removed []string) []string {
+	changes := make([]string, 0, len(added)+len(removed))
+	for _, id := range added {
+		changes = append(changes, "+"+id)
+	}
+	for _, id := range removed {
+		changes = append(changes, "-"+id)
+	}
+
+	return changes
+}
+
+func writeDiffTable(w io.Writer, diff Diff) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INTEGRATION\tMETRIC\tUNMONITORED\tUNMONITORED CHANGES\tMONITORED\tMONITORED CHANGES")
+
+	for _, d := range diff.Metrics {
+		fmt.Fprintf(tw, "%s\t%s\t+%d/-%d\t%s\t+%d/-%d\t%s\n", d.Integration, d.Metric,
+			len(d.AddedUnmonitored), len(d.RemovedUnmonitored), joinTableChanges(d.AddedUnmonitored, d.RemovedUnmonitored),
+			len(d.AddedMonitored), len(d.RemovedMonitored), joinTableChanges(d.AddedMonitored, d.RemovedMonitored))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if len(diff.AddedUnsupported) > 0 {
+		fmt.Fprintf(w, "\nNew unsupported metrics: %s\n", strings.Join(diff.AddedUnsupported, ", "))
+	}
+	if len(diff.RemovedUnsupported) > 0 {
+		fmt.Fprintf(w, "\nResolved unsupported metrics: %s\n", strings.Join(diff.RemovedUnsupported, ", "))
+	}
+
+	return nil
+}
+
+func writeDiffMarkdown(w io.Writer, diff Diff) error {
+	var sb strings.Builder
+	sb.WriteString("# modd scan diff\n")
+
+	if diff.Empty() {
+		sb.WriteString("\nNo changes.\n")
+	}
+
+	integration := ""
+	for _, d := range diff.Metrics {
+		if string(d.Integration) != integration {
+			integration = string(d.Integration)
+			fmt.Fprintf(&sb, "\n## %s\n\n", integration)
+			sb.WriteString("| Metric | Unmonitored | Unmonitored changes | Monitored | Monitored changes |\n")
+			sb.WriteString("|--------|------------:|---------------------|----------:|-------------------|\n")
+		}
+
+		fmt.Fprintf(&sb, "| `%s` | +%d/-%d | %s | +%d/-%d | %s |\n", d.Metric,
+			len(d.AddedUnmonitored), len(d.RemovedUnmonitored), joinMarkdownChanges(d.AddedUnmonitored, d.RemovedUnmonitored),
+			len(d.AddedMonitored), len(d.RemovedMonitored), joinMarkdownChanges(d.AddedMonitored, d.RemovedMonitored))
+	}
+
+	writeUnsupportedList(&sb, "New unsupported metrics", diff.AddedUnsupported)
+	writeUnsupportedList(&sb, "Resolved unsupported metrics", diff.RemovedUnsupported)
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+func joinTableChanges(added, removed []string) string {
+	changes := strings.Join(diffChanges(added, removed), ", ")
+	if changes == "" {
+		return "-"
+	}
+
+	return changes
+}
+
+func joinMarkdownChanges(added, removed []string) string {
+	changes := diffChanges(added, removed)
+	for i, c := range changes {
+		changes[i] = fmt.Sprintf("`%s`", escapeMarkdownCell(c))
+	}
+
+	return strings.Join(changes, ", ")
+}
+
+func writeUnsupportedList(sb *strings.Builder, title string, metrics []string) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n## %s\n\n", title)
+	for _, metric := range metrics {
+		fmt.Fprintf(sb, "* `%s`\n", metric)
+	}
+}
+
+// monitoredIdentifiers returns the resource identifiers covered by any monitor.
+func monitoredIdentifiers(m MetricResult) []string {
+	ids := make([]string, 0, len(m.Monitored))
+	for id := range m.Monitored {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// sortedDifference returns the sorted difference set of arguments.
+func sortedDifference(l1, l2 []string) []string {
+	d := filter.Difference(l1, l2)
+	sort.Strings(d)
+
+	return d
+}
@@ -0,0 +1,264 @@
+package report_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_CompareScans(t *testing.T) {
+	cases := []struct {
+		name     string
+		oldScan  report.Scan
+		newScan  report.Scan
+		expected report.Diff
+	}{
+		{
+			name: "when resources become monitored and unmonitored",
+			oldScan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+						},
+					},
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1", "db-2"},
+							Monitored:   map[string][]datadog.MonitorRef{},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}, "db-3": {{ID: 1}}},
+						},
+					},
+				},
+				Unsupported: []string{"aws.foo.bar"},
+			},
+			newScan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3", "db-4"},
+							Unmonitored: []string{"db-2", "db-4"},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 3}}},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}, "db-3": {{ID: 1}}},
+						},
+					},
+				},
+				Unsupported: []string{"aws.baz.qux"},
+			},
+			expected: report.Diff{
+				Metrics: []report.MetricDiff{
+					{
+						Metric:             "aws.rds.free_storage_space",
+						Integration:        datadog.AwsRds,
+						AddedUnmonitored:   []string{"db-4"},
+						RemovedUnmonitored: []string{"db-1"},
+						AddedMonitored:     []string{"db-1"},
+						RemovedMonitored:   []string{},
+					},
+					{
+						Metric:             "aws.sqs.number_of_messages_sent",
+						Integration:        datadog.AwsSqs,
+						AddedUnmonitored:   []string{},
+						RemovedUnmonitored: []string{},
+						AddedMonitored:     []string{},
+						RemovedMonitored:   []string{"queue-1"},
+					},
+				},
+				AddedUnsupported:   []string{"aws.baz.qux"},
+				RemovedUnsupported: []string{"aws.foo.bar"},
+			},
+		},
+		{
+			name: "when nothing is changed",
+			oldScan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2"},
+							Unmonitored: []string{"db-2"},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}},
+						},
+					},
+				},
+				Unsupported: []string{"aws.foo.bar"},
+			},
+			newScan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2"},
+							Unmonitored: []string{"db-2"},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}},
+						},
+					},
+				},
+				Unsupported: []string{"aws.foo.bar"},
+			},
+			expected: report.Diff{
+				Metrics:            []report.MetricDiff{},
+				AddedUnsupported:   []string{},
+				RemovedUnsupported: []string{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := report.CompareScans(c.oldScan, c.newScan)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_WriteDiff(t *testing.T) {
+	diff := report.Diff{
+		Metrics: []report.MetricDiff{
+			{
+				Metric:             "aws.rds.free_storage_space",
+				Integration:        datadog.AwsRds,
+				AddedUnmonitored:   []string{"db-4"},
+				RemovedUnmonitored: []string{"db-1"},
+				AddedMonitored:     []string{"db-1"},
+				RemovedMonitored:   []string{},
+			},
+			{
+				Metric:             "aws.sqs.number_of_messages_sent",
+				Integration:        datadog.AwsSqs,
+				AddedUnmonitored:   []string{},
+				RemovedUnmonitored: []string{},
+				AddedMonitored:     []string{},
+				RemovedMonitored:   []string{"queue-1"},
+			},
+		},
+		AddedUnsupported:   []string{"aws.baz.qux"},
+		RemovedUnsupported: []string{"aws.foo.bar"},
+	}
+
+	cases := []struct {
+		name     string
+		format   string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:   "when format is table",
+			format: "table",
+			expected: `INTEGRATION  METRIC                           UNMONITORED  UNMONITORED CHANGES  MONITORED  MONITORED CHANGES
+aws_rds      aws.rds.free_storage_space       +1/-1        +db-4, -db-1         +1/-0      +db-1
+aws_sqs      aws.sqs.number_of_messages_sent  +0/-0        -                    +0/-1      -queue-1
+
+New unsupported metrics: aws.baz.qux
+
+Resolved unsupported metrics: aws.foo.bar
+`,
+		},
+		{
+			name:   "when format is markdown",
+			format: "markdown",
+			expected: "# modd scan diff\n\n" +
+				"## aws_rds\n\n" +
+				"| Metric | Unmonitored | Unmonitored changes | Monitored | Monitored changes |\n" +
+				"|--------|------------:|---------------------|----------:|-------------------|\n" +
+				"| `aws.rds.free_storage_space` | +1/-1 | `+db-4`, `-db-1` | +1/-0 | `+db-1` |\n\n" +
+				"## aws_sqs\n\n" +
+				"| Metric | Unmonitored | Unmonitored changes | Monitored | Monitored changes |\n" +
+				"|--------|------------:|---------------------|----------:|-------------------|\n" +
+				"| `aws.sqs.number_of_messages_sent` | +0/-0 |  | +0/-1 | `-queue-1` |\n\n" +
+				"## New unsupported metrics\n\n" +
+				"* `aws.baz.qux`\n\n" +
+				"## Resolved unsupported metrics\n\n" +
+				"* `aws.foo.bar`\n",
+		},
+		{
+			name:   "when format is not supported",
+			format: "junit",
+			isErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		err := report.WriteDiff(&buf, diff, c.format, false)
+		if c.isErr {
+			if !assert.NotNil(t, err) {
+				t.Errorf("case: %s is failed, expected error\n", c.name)
+			}
+			continue
+		}
+
+		if !assert.Nil(t, err) || !assert.Equal(t, c.expected, buf.String()) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v, err: %+v\n", c.name, c.expected, buf.String(), err)
+		}
+	}
+}
+
+func Test_SaveScan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.json")
+	scan := report.Scan{
+		Metrics: []report.MetricResult{
+			{
+				Metric:      "aws.rds.cpuutilization",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2"},
+					Unmonitored: []string{"db-2"},
+					Excluded:    []string{},
+					Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1, Name: "rds cpu"}}},
+					Violations:  []string{"db-2"},
+					Stale:       []datadog.MonitorScope{},
+				},
+			},
+		},
+		Unsupported: []string{"aws.foo.bar"},
+	}
+
+	var buf bytes.Buffer
+	if err := report.SaveScan(&buf, scan); err != nil {
+		t.Fatalf("failed to save scan: %+v\n", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("failed to write scan: %+v\n", err)
+	}
+
+	actual, err := report.LoadScan(path)
+	if !assert.Nil(t, err) {
+		t.Fatalf("failed to load scan: %+v\n", err)
+	}
+	assert.Equal(t, scan, actual)
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+)
+
+type monitorStatus struct {
+	Name        string
+	Unmonitored []string
+	Monitored   map[string][]datadog.MonitorRef
+	Violations  []string
+	Stale       []datadog.MonitorScope
+}
+
+// WriteJSON writes unmonitored resources, covering monitors, required metrics violations and stale scopes per metric as JSON.
+func WriteJSON(w io.Writer, scan Scan, pretty bool) error {
+	monitorStatuses := make([]monitorStatus, 0, len(scan.Metrics))
+	for _, m := range scan.Metrics {
+		monitorStatuses = append(monitorStatuses, monitorStatus{
+			Name:        m.Metric,
+			Unmonitored: m.Unmonitored,
+			Monitored:   m.Monitored,
+			Violations:  m.Violations,
+			Stale:       m.Stale,
+		})
+	}
+
+	result := make(map[string]interface{})
+	result["Monitors"] = monitorStatuses
+	result["Unsupported"] = scan.Unsupported
+	result["StaleMonitors"] = scan.StaleMonitors
+	result["Errors"] = scan.Errors
+	result["Suppressed"] = scan.Suppressed
+	result["ExpiredSuppressions"] = scan.ExpiredSuppressions
+
+	return WriteJSONValue(w, result, pretty)
+}
+
+// WriteExplanation writes the explanation of a resource evaluation as JSON.
+func WriteExplanation(w io.Writer, explanation evaluator.Explanation, pretty bool) error {
+	return WriteJSONValue(w, explanation, pretty)
+}
+
+// WriteJSONValue writes the value as JSON, indented when pretty is true.
+func WriteJSONValue(w io.Writer, v interface{}, pretty bool) error {
+	var j []byte
+	var err error
+	if pretty {
+		j, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		j, err = json.Marshal(v)
+	}
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "%s", j); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
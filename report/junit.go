@@ -0,0 +1,109 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.Tests++
+	if tc.Failure != nil {
+		s.Failures++
+	}
+	if tc.Skipped != nil {
+		s.Skipped++
+	}
+	s.TestCases = append(s.TestCases, tc)
+}
+
+// WriteJUnit writes the scan result as JUnit XML to be shown on CI.
+// Each pair of metric and resource is a test case in the test suite of its integration;
+// unmonitored ones are failures, and excluded ones and unsupported metrics are skipped.
+func WriteJUnit(w io.Writer, scan Scan) error {
+	root := junitTestSuites{Name: "modd"}
+
+	for _, group := range groupByIntegration(scan) {
+		suite := junitTestSuite{Name: group.Integration}
+		for _, m := range group.Metrics {
+			statuses := resourceStatuses(m)
+			for _, id := range m.Resources {
+				tc := junitTestCase{ClassName: m.Metric, Name: id}
+				switch statuses[id] {
+				case StatusUnmonitored:
+					tc.Failure = &junitFailure{
+						Type:    string(StatusUnmonitored),
+						Message: fmt.Sprintf("%s is not monitored for %s", id, m.Metric),
+					}
+				case StatusExcluded:
+					tc.Skipped = &junitSkipped{Message: fmt.Sprintf("%s is excluded from %s", id, m.Metric)}
+				case StatusMonitored:
+				}
+				suite.add(tc)
+			}
+		}
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if len(scan.Unsupported) > 0 {
+		suite := junitTestSuite{Name: "unsupported"}
+		for _, metric := range scan.Unsupported {
+			suite.add(junitTestCase{
+				ClassName: metric,
+				Name:      metric,
+				Skipped:   &junitSkipped{Message: "the integration of the metric is not supported"},
+			})
+		}
+		root.Suites = append(root.Suites, suite)
+	}
+
+	for _, suite := range root.Suites {
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Skipped += suite.Skipped
+	}
+
+	b, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "%s%s\n", xml.Header, b); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_WriteJUnit(t *testing.T) {
+	cases := []struct {
+		name     string
+		scan     report.Scan
+		expected string
+	}{
+		{
+			name: "when some resources are unmonitored",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+						},
+					},
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1", "db-2"},
+							Excluded:    []string{"db-3"},
+							Monitored:   map[string][]datadog.MonitorRef{},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}, "db-3": {{ID: 1}}},
+						},
+					},
+				},
+				Unsupported: []string{"aws.foo.bar"},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="modd" tests="8" failures="3" skipped="2">
+  <testsuite name="aws_rds" tests="6" failures="3" skipped="1">
+    <testcase classname="aws.rds.cpuutilization" name="db-1"></testcase>
+    <testcase classname="aws.rds.cpuutilization" name="db-2">
+      <failure type="unmonitored" message="db-2 is not monitored for aws.rds.cpuutilization"></failure>
+    </testcase>
+    <testcase classname="aws.rds.cpuutilization" name="db-3"></testcase>
+    <testcase classname="aws.rds.free_storage_space" name="db-1">
+      <failure type="unmonitored" message="db-1 is not monitored for aws.rds.free_storage_space"></failure>
+    </testcase>
+    <testcase classname="aws.rds.free_storage_space" name="db-2">
+      <failure type="unmonitored" message="db-2 is not monitored for aws.rds.free_storage_space"></failure>
+    </testcase>
+    <testcase classname="aws.rds.free_storage_space" name="db-3">
+      <skipped message="db-3 is excluded from aws.rds.free_storage_space"></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="aws_sqs" tests="1" failures="0" skipped="0">
+    <testcase classname="aws.sqs.number_of_messages_sent" name="queue-1"></testcase>
+  </testsuite>
+  <testsuite name="unsupported" tests="1" failures="0" skipped="1">
+    <testcase classname="aws.foo.bar" name="aws.foo.bar">
+      <skipped message="the integration of the metric is not supported"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name: "when no metric is evaluated",
+			scan: report.Scan{
+				Metrics:     []report.MetricResult{},
+				Unsupported: []string{},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="modd" tests="0" failures="0" skipped="0"></testsuites>
+`,
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		err := report.WriteJUnit(&buf, c.scan)
+		if !assert.Nil(t, err) || !assert.Equal(t, c.expected, buf.String()) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v, err: %+v\n", c.name, c.expected, buf.String(), err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown writes the scan result as Markdown tables per integration, e.g. to be posted on pull requests.
+func WriteMarkdown(w io.Writer, scan Scan) error {
+	var sb strings.Builder
+	sb.WriteString("# modd scan result\n")
+
+	for _, group := range groupByIntegration(scan) {
+		fmt.Fprintf(&sb, "\n## %s\n\n", group.Integration)
+		sb.WriteString("| Metric | Monitored | Unmonitored | Excluded | Unmonitored resources |\n")
+		sb.WriteString("|--------|----------:|------------:|---------:|-----------------------|\n")
+
+		for _, m := range group.Metrics {
+			monitored, unmonitored, excluded := countStatusesOf(m)
+			resources := make([]string, len(m.Unmonitored))
+			for i, id := range m.Unmonitored {
+				resources[i] = fmt.Sprintf("`%s`", escapeMarkdownCell(id))
+			}
+
+			fmt.Fprintf(&sb, "| `%s` | %d | %d | %d | %s |\n", m.Metric, monitored, unmonitored, excluded, strings.Join(resources, ", "))
+		}
+	}
+
+	if len(scan.Unsupported) > 0 {
+		sb.WriteString("\n## Unsupported metrics\n\n")
+		for _, metric := range scan.Unsupported {
+			fmt.Fprintf(&sb, "* `%s`\n", metric)
+		}
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// escapeMarkdownCell escapes the pipe which breaks the table cell.
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
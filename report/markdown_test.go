@@ -0,0 +1,102 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_WriteMarkdown(t *testing.T) {
+	cases := []struct {
+		name     string
+		scan     report.Scan
+		expected string
+	}{
+		{
+			name: "when some resources are unmonitored",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+						},
+					},
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1", "db-2"},
+							Excluded:    []string{"db-3"},
+							Monitored:   map[string][]datadog.MonitorRef{},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}, "db-3": {{ID: 1}}},
+						},
+					},
+				},
+				Unsupported: []string{"aws.foo.bar"},
+			},
+			expected: "# modd scan result\n" +
+				"\n## aws_rds\n\n" +
+				"| Metric | Monitored | Unmonitored | Excluded | Unmonitored resources |\n" +
+				"|--------|----------:|------------:|---------:|-----------------------|\n" +
+				"| `aws.rds.cpuutilization` | 2 | 1 | 0 | `db-2` |\n" +
+				"| `aws.rds.free_storage_space` | 0 | 2 | 1 | `db-1`, `db-2` |\n" +
+				"\n## aws_sqs\n\n" +
+				"| Metric | Monitored | Unmonitored | Excluded | Unmonitored resources |\n" +
+				"|--------|----------:|------------:|---------:|-----------------------|\n" +
+				"| `aws.sqs.number_of_messages_sent` | 1 | 0 | 0 |  |\n" +
+				"\n## Unsupported metrics\n\n" +
+				"* `aws.foo.bar`\n",
+		},
+		{
+			name: "when every resource is monitored",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+						},
+					},
+				},
+				Unsupported: []string{},
+			},
+			expected: "# modd scan result\n" +
+				"\n## aws_sqs\n\n" +
+				"| Metric | Monitored | Unmonitored | Excluded | Unmonitored resources |\n" +
+				"|--------|----------:|------------:|---------:|-----------------------|\n" +
+				"| `aws.sqs.number_of_messages_sent` | 1 | 0 | 0 |  |\n",
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		err := report.WriteMarkdown(&buf, c.scan)
+		if !assert.Nil(t, err) || !assert.Equal(t, c.expected, buf.String()) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v, err: %+v\n", c.name, c.expected, buf.String(), err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+)
+
+// MetricResult represents the evaluation of a metric monitored by Datadog monitors.
+type MetricResult struct {
+	Metric      string
+	Integration datadog.IntegrationTarget
+	evaluator.Result
+}
+
+// Scan represents the result of a scan.
+// Unsupported holds the monitored metrics whose integration is not supported,
+// StaleMonitors holds the monitors matching no live resource,
+// Errors holds the failures of the metrics which could not be evaluated,
+// MapperErrors holds the integrations whose resources could not be fetched,
+// and Suppressed/ExpiredSuppressions hold the suppressions of the baseline applied or not applied due to expiry.
+type Scan struct {
+	Metrics             []MetricResult
+	Unsupported         []string
+	StaleMonitors       []datadog.MonitorRef
+	Errors              []string
+	MapperErrors        []datadog.IntegrationTarget
+	Suppressed          []Suppression
+	ExpiredSuppressions []Suppression
+}
+
+// Sort sorts the metrics and their resources to make the output stable.
+func (s *Scan) Sort() {
+	sort.Slice(s.Metrics, func(i, j int) bool {
+		return s.Metrics[i].Metric < s.Metrics[j].Metric
+	})
+
+	for i := 0; i < len(s.Metrics); i++ {
+		m := &s.Metrics[i]
+		sort.Strings(m.Resources)
+		sort.Strings(m.Unmonitored)
+		sort.Strings(m.Excluded)
+		sort.Strings(m.Violations)
+		sort.SliceStable(m.Stale, func(j, k int) bool {
+			return m.Stale[j].Monitor.ID < m.Stale[k].Monitor.ID
+		})
+		for _, refs := range m.Monitored {
+			sort.Slice(refs, func(j, k int) bool {
+				return refs[j].ID < refs[k].ID
+			})
+		}
+	}
+
+	sort.Strings(s.Unsupported)
+	sort.Strings(s.Errors)
+	sort.Slice(s.MapperErrors, func(i, j int) bool {
+		return s.MapperErrors[i] < s.MapperErrors[j]
+	})
+}
+
+// Write writes the scan result in the format.
+func Write(w io.Writer, scan Scan, format string, pretty bool) error {
+	switch format {
+	case config.JSONFormat:
+		return WriteJSON(w, scan, pretty)
+	case config.CoverageFormat:
+		return WriteCoverage(w, scan, pretty)
+	case config.TableFormat:
+		return WriteTable(w, scan)
+	case config.MarkdownFormat:
+		return WriteMarkdown(w, scan)
+	case config.JUnitFormat:
+		return WriteJUnit(w, scan)
+	case config.SARIFFormat:
+		return WriteSARIF(w, scan, pretty)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	sarifSchema  = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	sarifRuleUnmonitored       = "unmonitored-resource"
+	sarifRuleRequiredViolation = "required-metric-violation"
+	sarifRuleUnsupported       = "unsupported-metric"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Kind                string            `json:"kind"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the scan result as SARIF to be uploaded to code scanning tools.
+// Each unmonitored pair of metric and resource is a finding located at the resource,
+// which is an error when the metric is required for the resource, and unsupported metrics are notes.
+func WriteSARIF(w io.Writer, scan Scan, pretty bool) error {
+	results := make([]sarifResult, 0)
+	for _, group := range groupByIntegration(scan) {
+		for _, m := range group.Metrics {
+			violations := make(map[string]struct{}, len(m.Violations))
+			for _, id := range m.Violations {
+				violations[id] = struct{}{}
+			}
+
+			for _, id := range m.Unmonitored {
+				result := sarifResult{
+					RuleID:  sarifRuleUnmonitored,
+					Kind:    "fail",
+					Level:   "warning",
+					Message: sarifMessage{Text: fmt.Sprintf("%s is not monitored for %s", id, m.Metric)},
+				}
+				if _, ok := violations[id]; ok {
+					result.RuleID = sarifRuleRequiredViolation
+					result.Level = "error"
+					result.Message.Text = fmt.Sprintf("%s is not monitored for the required metric %s", id, m.Metric)
+				}
+
+				result.Locations = []sarifLocation{newSarifLocation(id, fmt.Sprintf("%s/%s", group.Integration, id))}
+				result.PartialFingerprints = map[string]string{"moddPair/v1": fmt.Sprintf("%s/%s", m.Metric, id)}
+				results = append(results, result)
+			}
+		}
+	}
+
+	for _, metric := range scan.Unsupported {
+		results = append(results, sarifResult{
+			RuleID:              sarifRuleUnsupported,
+			Kind:                "notApplicable",
+			Level:               "none",
+			Message:             sarifMessage{Text: fmt.Sprintf("the integration of %s is not supported", metric)},
+			Locations:           []sarifLocation{newSarifLocation(metric, metric)},
+			PartialFingerprints: map[string]string{"moddMetric/v1": metric},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "modd",
+						InformationURI: "https://github.com/terakoya76/modd",
+						Rules: []sarifRule{
+							{ID: sarifRuleUnmonitored, ShortDescription: sarifMessage{Text: "Resource is not monitored for the metric"}},
+							{ID: sarifRuleRequiredViolation, ShortDescription: sarifMessage{Text: "Resource is not monitored for the required metric"}},
+							{ID: sarifRuleUnsupported, ShortDescription: sarifMessage{Text: "Integration of the metric is not supported"}},
+						},
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	return WriteJSONValue(w, log, pretty)
+}
+
+func newSarifLocation(name, fullyQualifiedName string) sarifLocation {
+	return sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{
+			{Name: name, FullyQualifiedName: fullyQualifiedName, Kind: "resource"},
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_WriteSARIF(t *testing.T) {
+	type finding struct {
+		ruleID   string
+		level    string
+		location string
+	}
+
+	cases := []struct {
+		name     string
+		scan     report.Scan
+		expected []finding
+	}{
+		{
+			name: "when some resources are unmonitored",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+						},
+					},
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1", "db-2"},
+							Excluded:    []string{"db-3"},
+							Monitored:   map[string][]datadog.MonitorRef{},
+							Violations:  []string{"db-1"},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}, "db-3": {{ID: 1}}},
+						},
+					},
+				},
+				Unsupported: []string{"aws.foo.bar"},
+			},
+			expected: []finding{
+				{ruleID: "unmonitored-resource", level: "warning", location: "aws_rds/db-2"},
+				{ruleID: "required-metric-violation", level: "error", location: "aws_rds/db-1"},
+				{ruleID: "unmonitored-resource", level: "warning", location: "aws_rds/db-2"},
+				{ruleID: "unsupported-metric", level: "none", location: "aws.foo.bar"},
+			},
+		},
+		{
+			name: "when every resource is monitored",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+						},
+					},
+				},
+				Unsupported: []string{},
+			},
+			expected: []finding{},
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		if err := report.WriteSARIF(&buf, c.scan, false); err != nil {
+			t.Fatalf("case: %s is failed, err: %+v\n", c.name, err)
+		}
+
+		var log struct {
+			Version string `json:"version"`
+			Runs    []struct {
+				Results []struct {
+					RuleID    string `json:"ruleId"`
+					Level     string `json:"level"`
+					Locations []struct {
+						LogicalLocations []struct {
+							FullyQualifiedName string `json:"fullyQualifiedName"`
+						} `json:"logicalLocations"`
+					} `json:"locations"`
+				} `json:"results"`
+			} `json:"runs"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+			t.Fatalf("case: %s is failed, failed to unmarshal SARIF: %+v\n", c.name, err)
+		}
+
+		assert.Equal(t, "2.1.0", log.Version)
+		if !assert.Len(t, log.Runs, 1) {
+			t.Errorf("case: %s is failed, runs: %+v\n", c.name, log.Runs)
+			continue
+		}
+
+		actual := make([]finding, 0)
+		for _, r := range log.Runs[0].Results {
+			actual = append(actual, finding{
+				ruleID:   r.RuleID,
+				level:    r.Level,
+				location: r.Locations[0].LogicalLocations[0].FullyQualifiedName,
+			})
+		}
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
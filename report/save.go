@@ -0,0 +1,28 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SaveScan writes the whole scan result as JSON to be compared with a later scan.
+func SaveScan(w io.Writer, scan Scan) error {
+	return WriteJSONValue(w, scan, true)
+}
+
+// LoadScan reads the scan result saved by SaveScan.
+func LoadScan(path string) (Scan, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Scan{}, fmt.Errorf("failed to read scan result: %w", err)
+	}
+
+	var scan Scan
+	if err := json.Unmarshal(b, &scan); err != nil {
+		return Scan{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return scan, nil
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/terakoya76/modd/datadog"
+)
+
+// FindStaleMonitors returns the monitors whose every scope of the evaluated metrics matches no live resource.
+// Metrics of unsupported integrations are not taken into account since their resources are unknown.
+func FindStaleMonitors(monitorScopesMapping datadog.MonitorScopesMapping, metrics []MetricResult) []datadog.MonitorRef {
+	total := make(map[int64]int)
+	stale := make(map[int64]int)
+	refs := make(map[int64]datadog.MonitorRef)
+
+	for _, m := range metrics {
+		for _, ms := range monitorScopesMapping[m.Metric] {
+			total[ms.Monitor.ID]++
+			refs[ms.Monitor.ID] = ms.Monitor
+		}
+
+		for _, ms := range m.Stale {
+			stale[ms.Monitor.ID]++
+		}
+	}
+
+	monitors := make([]datadog.MonitorRef, 0)
+	for id, n := range stale {
+		if n >= total[id] {
+			monitors = append(monitors, refs[id])
+		}
+	}
+
+	sort.Slice(monitors, func(i, j int) bool {
+		return monitors[i].ID < monitors[j].ID
+	})
+
+	return monitors
+}
@@ -0,0 +1,58 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_FindStaleMonitors(t *testing.T) {
+	live := datadog.MonitorRef{ID: 1, Name: "live"}
+	partial := datadog.MonitorRef{ID: 2, Name: "partially stale"}
+	stale := datadog.MonitorRef{ID: 3, Name: "stale"}
+	unsupported := datadog.MonitorRef{ID: 4, Name: "unsupported"}
+
+	mapping := datadog.MonitorScopesMapping{
+		"aws.rds.cpuutilization": {
+			{Monitor: live, Scope: datadog.Scope{"*"}},
+			{Monitor: partial, Scope: datadog.Scope{"env:prod"}},
+			{Monitor: stale, Scope: datadog.Scope{"dbinstanceidentifier:deleted-db"}},
+		},
+		"aws.rds.free_storage_space": {
+			{Monitor: partial, Scope: datadog.Scope{"env:dev"}},
+			{Monitor: stale, Scope: datadog.Scope{"dbinstanceidentifier:deleted-db"}},
+		},
+		"aws.foo.bar": {
+			{Monitor: unsupported, Scope: datadog.Scope{"*"}},
+		},
+	}
+
+	metrics := []report.MetricResult{
+		{
+			Metric:      "aws.rds.cpuutilization",
+			Integration: datadog.AwsRds,
+			Result: evaluator.Result{
+				Stale: []datadog.MonitorScope{
+					{Monitor: stale, Scope: datadog.Scope{"dbinstanceidentifier:deleted-db"}},
+				},
+			},
+		},
+		{
+			Metric:      "aws.rds.free_storage_space",
+			Integration: datadog.AwsRds,
+			Result: evaluator.Result{
+				Stale: []datadog.MonitorScope{
+					{Monitor: partial, Scope: datadog.Scope{"env:dev"}},
+					{Monitor: stale, Scope: datadog.Scope{"dbinstanceidentifier:deleted-db"}},
+				},
+			},
+		},
+	}
+
+	actual := report.FindStaleMonitors(mapping, metrics)
+	assert.Equal(t, []datadog.MonitorRef{stale}, actual)
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// integrationMetrics represents the metrics of an integration.
+type integrationMetrics struct {
+	Integration string
+	Metrics     []MetricResult
+}
+
+// groupByIntegration groups the metrics by integration sorted by integration and metric.
+func groupByIntegration(scan Scan) []integrationMetrics {
+	byIntegration := make(map[string][]MetricResult)
+	for _, m := range scan.Metrics {
+		it := string(m.Integration)
+		byIntegration[it] = append(byIntegration[it], m)
+	}
+
+	groups := make([]integrationMetrics, 0, len(byIntegration))
+	for it, metrics := range byIntegration {
+		sort.Slice(metrics, func(i, j int) bool {
+			return metrics[i].Metric < metrics[j].Metric
+		})
+		groups = append(groups, integrationMetrics{Integration: it, Metrics: metrics})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Integration < groups[j].Integration
+	})
+
+	return groups
+}
+
+// countStatusesOf returns the number of resources per status of the metric.
+func countStatusesOf(m MetricResult) (monitored, unmonitored, excluded int) {
+	unmonitored = len(m.Unmonitored)
+	excluded = len(m.Excluded)
+	monitored = len(m.Resources) - unmonitored - excluded
+
+	return monitored, unmonitored, excluded
+}
+
+// WriteTable writes the scan result as a plain text table grouped by integration and metric.
+func WriteTable(w io.Writer, scan Scan) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INTEGRATION\tMETRIC\tMONITORED\tUNMONITORED\tEXCLUDED\tUNMONITORED RESOURCES")
+
+	for _, group := range groupByIntegration(scan) {
+		for _, m := range group.Metrics {
+			monitored, unmonitored, excluded := countStatusesOf(m)
+			resources := strings.Join(m.Unmonitored, ", ")
+			if resources == "" {
+				resources = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\n", group.Integration, m.Metric, monitored, unmonitored, excluded, resources)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if len(scan.Unsupported) > 0 {
+		if _, err := fmt.Fprintf(w, "\nUnsupported metrics: %s\n", strings.Join(scan.Unsupported, ", ")); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	return nil
+}
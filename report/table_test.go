@@ -0,0 +1,95 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_WriteTable(t *testing.T) {
+	cases := []struct {
+		name     string
+		scan     report.Scan
+		expected string
+	}{
+		{
+			name: "when some resources are unmonitored",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+						},
+					},
+					{
+						Metric:      "aws.rds.free_storage_space",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-1", "db-2"},
+							Excluded:    []string{"db-3"},
+							Monitored:   map[string][]datadog.MonitorRef{},
+						},
+					},
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}, "db-3": {{ID: 1}}},
+						},
+					},
+				},
+				Unsupported: []string{"aws.foo.bar"},
+			},
+			expected: `INTEGRATION  METRIC                           MONITORED  UNMONITORED  EXCLUDED  UNMONITORED RESOURCES
+aws_rds      aws.rds.cpuutilization           2          1            0         db-2
+aws_rds      aws.rds.free_storage_space       0          2            1         db-1, db-2
+aws_sqs      aws.sqs.number_of_messages_sent  1          0            0         -
+
+Unsupported metrics: aws.foo.bar
+`,
+		},
+		{
+			name: "when every resource is monitored",
+			scan: report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{},
+							Excluded:    []string{},
+							Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+						},
+					},
+				},
+				Unsupported: []string{},
+			},
+			expected: `INTEGRATION  METRIC                           MONITORED  UNMONITORED  EXCLUDED  UNMONITORED RESOURCES
+aws_sqs      aws.sqs.number_of_messages_sent  1          0            0         -
+`,
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		err := report.WriteTable(&buf, c.scan)
+		if !assert.Nil(t, err) || !assert.Equal(t, c.expected, buf.String()) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v, err: %+v\n", c.name, c.expected, buf.String(), err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/terakoya76/modd/config"
+)
+
+// CheckThresholds returns the descriptions of the thresholds breached by the scan result.
+// An integration which is not scanned is not taken into account for MinCoverage.
+func CheckThresholds(scan Scan, thresholds config.Thresholds) []string {
+	breaches := make([]string, 0)
+
+	unmonitored := 0
+	for _, m := range scan.Metrics {
+		unmonitored += len(m.Unmonitored)
+	}
+	if unmonitored > thresholds.MaxUnmonitored {
+		breaches = append(breaches, fmt.Sprintf("%d unmonitored resources exceed max_unmonitored %d", unmonitored, thresholds.MaxUnmonitored))
+	}
+
+	for _, ic := range BuildCoverageMatrix(scan).Integrations {
+		min, ok := thresholds.MinCoverage[ic.Integration]
+		if ok && ic.Coverage < min {
+			breaches = append(breaches, fmt.Sprintf("coverage %.1f%% of %s is below min_coverage %g%%", ic.Coverage, ic.Integration, min))
+		}
+	}
+
+	return breaches
+}
+
+// ExitCode returns the exit code of the scan.
+// A partial failure takes precedence over breaches since the result is incomplete.
+func ExitCode(scan Scan, breaches []string, codes config.ExitCodes) int {
+	switch {
+	case len(scan.Errors) > 0:
+		return codes.PartialFailure
+	case len(breaches) > 0:
+		return codes.Unmonitored
+	default:
+		return 0
+	}
+}
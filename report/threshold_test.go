@@ -0,0 +1,109 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+func Test_CheckThresholds(t *testing.T) {
+	scan := report.Scan{
+		Metrics: []report.MetricResult{
+			{
+				Metric:      "aws.rds.cpuutilization",
+				Integration: datadog.AwsRds,
+				Result: evaluator.Result{
+					Resources:   []string{"db-1", "db-2"},
+					Unmonitored: []string{"db-2"},
+					Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1}}},
+				},
+			},
+			{
+				Metric:      "aws.sqs.number_of_messages_sent",
+				Integration: datadog.AwsSqs,
+				Result: evaluator.Result{
+					Resources:   []string{"queue-1"},
+					Unmonitored: []string{},
+					Monitored:   map[string][]datadog.MonitorRef{"queue-1": {{ID: 2}}},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		thresholds config.Thresholds
+		expected   []string
+	}{
+		{
+			name:       "when no unmonitored resource is allowed",
+			thresholds: config.Thresholds{},
+			expected:   []string{"1 unmonitored resources exceed max_unmonitored 0"},
+		},
+		{
+			name: "when thresholds are satisfied",
+			thresholds: config.Thresholds{
+				MaxUnmonitored: 1,
+				MinCoverage:    map[string]float64{"aws_rds": 50, "aws_sqs": 100, "aws_ec2": 100},
+			},
+			expected: []string{},
+		},
+		{
+			name: "when coverage is below min coverage",
+			thresholds: config.Thresholds{
+				MaxUnmonitored: 1,
+				MinCoverage:    map[string]float64{"aws_rds": 80},
+			},
+			expected: []string{"coverage 50.0% of aws_rds is below min_coverage 80%"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := report.CheckThresholds(scan, c.thresholds)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %+v, actual: %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
+
+func Test_ExitCode(t *testing.T) {
+	codes := config.ExitCodes{Unmonitored: 2, PartialFailure: 3, Fatal: 1}
+
+	cases := []struct {
+		name     string
+		scan     report.Scan
+		breaches []string
+		expected int
+	}{
+		{
+			name:     "when nothing is breached",
+			scan:     report.Scan{},
+			breaches: []string{},
+			expected: 0,
+		},
+		{
+			name:     "when threshold is breached",
+			scan:     report.Scan{},
+			breaches: []string{"breach"},
+			expected: 2,
+		},
+		{
+			name:     "when some metrics failed",
+			scan:     report.Scan{Errors: []string{"failure"}},
+			breaches: []string{"breach"},
+			expected: 3,
+		},
+	}
+
+	for _, c := range cases {
+		actual := report.ExitCode(c.scan, c.breaches, codes)
+		if !assert.Equal(t, c.expected, actual) {
+			t.Errorf("case: %s is failed, expected: %d, actual: %d\n", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+)
+
+// runScan detects resources unmonitored by Datadog monitors.
+func runScan(args []string, stdout, stderr io.Writer) int {
+	var cf commonFlags
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	cf.register(fs)
+	cf.registerFormat(fs)
+	cf.registerScan(fs)
+	cf.registerBaseline(fs)
+	cf.registerHistory(fs)
+	cf.registerPublish(fs)
+	integrations := fs.String("integrations", "", "comma-separated integrations to be scanned, e.g. aws_rds,aws_sqs (all by default)")
+	save := fs.String("save", "", "path to save the scan result to be compared by the diff command")
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	its, err := parseIntegrations(*integrations)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+
+	codes := cfg.Exit.Codes
+	scannedAt := time.Now()
+	scan, err := runScanner(ctx, cfg, its, cf.concurrency, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return codes.Fatal
+	}
+
+	if err = applyBaseline(cfg, &scan, stderr); err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return codes.Fatal
+	}
+
+	var previous *report.Scan
+	if cfg.History != "" {
+		previous, err = recordHistory(cfg.History, scannedAt, scan)
+		if err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return codes.Fatal
+		}
+	}
+
+	// a failure to publish does not discard the scan result to be written, and raises the exit code afterwards
+	published := true
+	if cfg.Datadog.Publish {
+		if err = report.PublishDatadog(ctx, datadog.GetDatadogClient(), scan, previous, scannedAt); err != nil {
+			fmt.Fprintf(stderr, "failed to publish to Datadog: %v\n", err)
+			published = false
+		}
+	}
+
+	if *save != "" {
+		if err := saveScan(*save, scan); err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return codes.Fatal
+		}
+	}
+
+	if err := report.Write(stdout, scan, cfg.Output.Format, cfg.Output.Pretty); err != nil {
+		fmt.Fprintf(stderr, "failed to write result: %v\n", err)
+		return codes.Fatal
+	}
+
+	breaches := report.CheckThresholds(scan, cfg.Exit.Thresholds)
+	for _, breach := range breaches {
+		fmt.Fprintf(stderr, "%s\n", breach)
+	}
+
+	if !published {
+		return codes.Fatal
+	}
+
+	return report.ExitCode(scan, breaches, codes)
+}
+
+// applyBaseline suppresses the unmonitored pairs accepted by the configured baseline, and reports the expired suppressions.
+func applyBaseline(cfg *config.Config, scan *report.Scan, stderr io.Writer) error {
+	if cfg.Baseline == "" {
+		return nil
+	}
+
+	baseline, err := report.LoadBaseline(cfg.Baseline, false)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	scan.ApplyBaseline(baseline, time.Now())
+	for _, s := range scan.ExpiredSuppressions {
+		fmt.Fprintf(stderr, "suppression of %s for %s expired on %s\n", s.Resource, s.Metric, s.Expires)
+	}
+
+	return nil
+}
+
+// saveScan saves the scan result into the file.
+func saveScan(path string, scan report.Scan) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create scan result file: %w", err)
+	}
+
+	if err := report.SaveScan(f, scan); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to save scan result: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close scan result file: %w", err)
+	}
+
+	return nil
+}
+
+// runScanner fetches the monitors and evaluates the resources of the integrations.
+func runScanner(
+	ctx context.Context, cfg *config.Config, its []datadog.IntegrationTarget, concurrency int, stderr io.Writer,
+) (report.Scan, error) {
+	ddMonitorScopesMapping, err := getMonitorScopesMapping(ctx, cfg, concurrency)
+	if err != nil {
+		return report.Scan{}, err
+	}
+
+	scan, err := checkUnmonitored(ctx, cfg, its, concurrency, ddMonitorScopesMapping, stderr)
+	if err != nil {
+		return report.Scan{}, fmt.Errorf("failed to check monitor status: %w", err)
+	}
+
+	return scan, nil
+}
+
+// checkUnmonitored evaluates the metrics of the integrations at most concurrency metrics at once.
+// Every supported integration is evaluated when its is empty.
+func checkUnmonitored(
+	ctx context.Context,
+	cfg *config.Config,
+	its []datadog.IntegrationTarget,
+	concurrency int,
+	monitorScopesMapping datadog.MonitorScopesMapping,
+	stderr io.Writer,
+) (report.Scan, error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, concurrency)
+
+	scan := report.Scan{
+		Metrics:     make([]report.MetricResult, 0),
+		Unsupported: make([]string, 0),
+		Errors:      make([]string, 0),
+
+		MapperErrors:        make([]datadog.IntegrationTarget, 0),
+		Suppressed:          make([]report.Suppression, 0),
+		ExpiredSuppressions: make([]report.Suppression, 0),
+	}
+	evaluators := newEvaluatorCache(cfg)
+	for metric, scopes := range withRequiredMetrics(cfg, monitorScopesMapping) {
+		it := datadog.MetricToIntegrationTarget(metric)
+		if it == datadog.UnknownIntegration {
+			if len(its) == 0 {
+				scan.Unsupported = append(scan.Unsupported, metric)
+			}
+			continue
+		}
+
+		if !containsIntegration(its, it) {
+			continue
+		}
+
+		e, err := evaluators.get(ctx, it)
+		if err != nil {
+			var tmErr *evaluator.TagsMappingError
+			if !errors.As(err, &tmErr) {
+				return report.Scan{}, fmt.Errorf("failed to get Evaluator object: %w", err)
+			}
+
+			mu.Lock()
+			recordEvaluationError(&scan, metric, err, stderr)
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(metric string, scopes []datadog.MonitorScope) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := e.Evaluate(ctx, metric, scopes)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				recordEvaluationError(&scan, metric, err, stderr)
+				return
+			}
+
+			scan.Metrics = append(scan.Metrics, report.MetricResult{
+				Metric:      metric,
+				Integration: it,
+				Result:      result,
+			})
+		}(metric, scopes)
+	}
+
+	wg.Wait()
+	scan.StaleMonitors = report.FindStaleMonitors(monitorScopesMapping, scan.Metrics)
+	scan.Sort()
+
+	return scan, nil
+}
+
+// evaluatorCache builds an Evaluator per integration to be shared by every metric of the integration in a scan,
+// so that the resources of the integration are fetched once and every metric is evaluated with the same resources.
+type evaluatorCache struct {
+	cfg        *config.Config
+	evaluators map[datadog.IntegrationTarget]evaluator.Evaluator
+	errs       map[datadog.IntegrationTarget]error
+}
+
+func newEvaluatorCache(cfg *config.Config) *evaluatorCache {
+	return &evaluatorCache{
+		cfg:        cfg,
+		evaluators: make(map[datadog.IntegrationTarget]evaluator.Evaluator),
+		errs:       make(map[datadog.IntegrationTarget]error),
+	}
+}
+
+// get returns the Evaluator of the integration, and the error of the first build when it failed.
+func (c *evaluatorCache) get(ctx context.Context, it datadog.IntegrationTarget) (evaluator.Evaluator, error) {
+	if err, ok := c.errs[it]; ok {
+		return evaluator.Evaluator{}, err
+	}
+
+	if e, ok := c.evaluators[it]; ok {
+		return e, nil
+	}
+
+	e, err := evaluator.BuildEvaluator(ctx, it, c.cfg.Integration(it))
+	if err != nil {
+		c.errs[it] = err
+		return evaluator.Evaluator{}, err
+	}
+	c.evaluators[it] = e
+
+	return e, nil
+}
+
+// recordEvaluationError records the failure to evaluate the metric into the scan result.
+// The integration is recorded in MapperErrors when its resources cannot be fetched.
+func recordEvaluationError(scan *report.Scan, metric string, err error, stderr io.Writer) {
+	fmt.Fprintf(stderr, "failed to filter monitors: %v\n", err)
+	scan.Errors = append(scan.Errors, fmt.Sprintf("%s: %v", metric, err))
+
+	var tmErr *evaluator.TagsMappingError
+	if errors.As(err, &tmErr) {
+		scan.MapperErrors = appendIntegration(scan.MapperErrors, tmErr.Integration)
+	}
+}
+
+// withRequiredMetrics adds the required metrics without monitors to the mapping
+// so that their violations are reported.
+func withRequiredMetrics(cfg *config.Config, monitorScopesMapping datadog.MonitorScopesMapping) datadog.MonitorScopesMapping {
+	mapping := make(datadog.MonitorScopesMapping, len(monitorScopesMapping))
+	for metric, scopes := range monitorScopesMapping {
+		mapping[metric] = scopes
+	}
+
+	for _, ic := range cfg.Integrations {
+		for _, rule := range ic.RequiredMetrics {
+			for _, metric := range rule.Metrics {
+				metric = strings.ToLower(metric)
+				if _, ok := mapping[metric]; !ok {
+					mapping[metric] = []datadog.MonitorScope{}
+				}
+			}
+		}
+	}
+
+	return mapping
+}
+
+// appendIntegration appends the integration unless it is already included.
+// The metrics of the same integration share the failure of fetching their resources.
+func appendIntegration(its []datadog.IntegrationTarget, it datadog.IntegrationTarget) []datadog.IntegrationTarget {
+	for _, i := range its {
+		if i == it {
+			return its
+		}
+	}
+
+	return append(its, it)
+}
+
+// containsIntegration returns true when its is empty, which means every integration.
+func containsIntegration(its []datadog.IntegrationTarget, it datadog.IntegrationTarget) bool {
+	if len(its) == 0 {
+		return true
+	}
+
+	for _, i := range its {
+		if i == it {
+			return true
+		}
+	}
+
+	return false
+}
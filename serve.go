@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/terakoya76/modd/config"
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/report"
+	"github.com/terakoya76/modd/server"
+)
+
+// shutdownTimeout represents the time to wait for in-flight requests on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// runServe rescans the resources periodically, and serves the latest scan result over HTTP until interrupted.
+func runServe(args []string, stdout, stderr io.Writer) int {
+	var cf commonFlags
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	cf.register(fs)
+	cf.registerScan(fs)
+	cf.registerBaseline(fs)
+	cf.registerHistory(fs)
+	cf.registerPublish(fs)
+	integrations := fs.String("integrations", "", "comma-separated integrations to be scanned, e.g. aws_rds,aws_sqs (all by default)")
+	listen := fs.String("listen", ":8080", "address to listen on")
+	interval := fs.Duration("interval", time.Hour, "interval of rescans")
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	its, err := parseIntegrations(*integrations)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	if *interval <= 0 {
+		fmt.Fprintf(stderr, "interval must be positive\n")
+		return 1
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(datadog.GetDatadogContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := server.New(newServeScanner(&cf, cfg, its, stderr), *interval)
+	go srv.Run(ctx, func(err error) {
+		fmt.Fprintf(stderr, "%v\n", err)
+	})
+
+	httpServer := &http.Server{
+		Addr:              *listen,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+	fmt.Fprintf(stdout, "listening on %s\n", *listen)
+
+	select {
+	case err := <-errCh:
+		fmt.Fprintf(stderr, "failed to serve: %v\n", err)
+		return cfg.Exit.Codes.Fatal
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "failed to shutdown: %v\n", err)
+		return cfg.Exit.Codes.Fatal
+	}
+
+	return 0
+}
+
+// newServeScanner returns the scanner which applies the baseline, records the history and publishes to Datadog same as the scan command.
+// Each scan is bounded by -timeout.
+// New gaps are found against the previous scan of the process unless the history is configured.
+func newServeScanner(cf *commonFlags, cfg *config.Config, its []datadog.IntegrationTarget, stderr io.Writer) server.Scanner {
+	var last *report.Scan
+	return func(ctx context.Context) (report.Scan, error) {
+		if cf.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cf.timeout)
+			defer cancel()
+		}
+
+		scannedAt := time.Now()
+		scan, err := runScanner(ctx, cfg, its, cf.concurrency, stderr)
+		if err != nil {
+			return report.Scan{}, err
+		}
+
+		if err = applyBaseline(cfg, &scan, stderr); err != nil {
+			return report.Scan{}, err
+		}
+
+		previous := last
+		if cfg.History != "" {
+			previous, err = recordHistory(cfg.History, scannedAt, scan)
+			if err != nil {
+				return report.Scan{}, err
+			}
+		}
+
+		// a failure to publish does not discard the scan result to be served
+		if cfg.Datadog.Publish {
+			if err := report.PublishDatadog(ctx, datadog.GetDatadogClient(), scan, previous, scannedAt); err != nil {
+				fmt.Fprintf(stderr, "failed to publish to Datadog: %v\n", err)
+			}
+		}
+		last = &scan
+
+		return scan, nil
+	}
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/report"
+)
+
+// scanResponse represents the response of /v1/scan.
+// LastError is the error of the last scan which keeps the previous result.
+type scanResponse struct {
+	ScannedAt time.Time
+	Duration  time.Duration
+	LastError string
+	report.Scan
+}
+
+// integrationResponse represents the response of /v1/integrations/{it}.
+type integrationResponse struct {
+	ScannedAt time.Time
+	Coverage  report.IntegrationCoverage
+	Metrics   []report.MetricResult
+}
+
+// resourceResponse represents the response of /v1/resources/{id} and /v1/resources?id={id}.
+type resourceResponse struct {
+	ScannedAt time.Time
+	Resource  string
+	Metrics   []resourceMetric
+}
+
+// resourceMetric represents the monitoring status of a resource for a metric.
+// Required is true when the metric is required for the unmonitored resource.
+type resourceMetric struct {
+	Integration datadog.IntegrationTarget
+	Metric      string
+	Status      report.Status
+	Monitors    []datadog.MonitorRef
+	Required    bool
+}
+
+type errorResponse struct {
+	Error string
+}
+
+// Handler returns the HTTP handler serving the latest scan result.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/scan", s.withSnapshot(s.handleScan))
+	mux.HandleFunc("/v1/integrations/", s.withSnapshot(s.handleIntegration))
+	mux.HandleFunc("/v1/resources", s.withSnapshot(s.handleResource))
+	mux.Handle("/metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
+
+	// ServeMux redirects the paths containing "//" to the cleaned ones, which breaks the IDs such as URLs.
+	resource := s.withSnapshot(s.handleResource)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v1/resources/") {
+			resource(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// withSnapshot rejects requests other than GET, and responds 503 until the first scan succeeds.
+func (s *Server) withSnapshot(h func(w http.ResponseWriter, r *http.Request, snapshot Snapshot)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		snapshot, ok := s.Latest()
+		if !ok {
+			msg := "no scan has completed yet"
+			if lastError := s.LastError(); lastError != "" {
+				msg = lastError
+			}
+			writeError(w, http.StatusServiceUnavailable, msg)
+			return
+		}
+
+		h(w, r, snapshot)
+	}
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, _ *http.Request, snapshot Snapshot) {
+	writeResponse(w, http.StatusOK, scanResponse{
+		ScannedAt: snapshot.ScannedAt,
+		Duration:  snapshot.Duration,
+		LastError: s.LastError(),
+		Scan:      snapshot.Scan,
+	})
+}
+
+func (s *Server) handleIntegration(w http.ResponseWriter, r *http.Request, snapshot Snapshot) {
+	it := strings.TrimPrefix(r.URL.Path, "/v1/integrations/")
+
+	metrics := make([]report.MetricResult, 0)
+	for _, m := range snapshot.Scan.Metrics {
+		if string(m.Integration) == it {
+			metrics = append(metrics, m)
+		}
+	}
+
+	if len(metrics) == 0 {
+		writeError(w, http.StatusNotFound, "integration not found: "+it)
+		return
+	}
+
+	matrix := report.BuildCoverageMatrix(report.Scan{Metrics: metrics})
+	writeResponse(w, http.StatusOK, integrationResponse{
+		ScannedAt: snapshot.ScannedAt,
+		Coverage:  matrix.Integrations[0],
+		Metrics:   metrics,
+	})
+}
+
+// handleResource responds the status of the resource given by /v1/resources/{id} or /v1/resources?id={id}.
+// The ID in the path may be escaped, e.g. %2F for "/".
+func (s *Server) handleResource(w http.ResponseWriter, r *http.Request, snapshot Snapshot) {
+	id := r.URL.Query().Get("id")
+	if strings.HasPrefix(r.URL.Path, "/v1/resources/") {
+		var err error
+		id, err = url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/v1/resources/"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid resource id: "+err.Error())
+			return
+		}
+	}
+
+	metrics, ok := snapshot.resources[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "resource not found: "+id)
+		return
+	}
+
+	writeResponse(w, http.StatusOK, resourceResponse{
+		ScannedAt: snapshot.ScannedAt,
+		Resource:  id,
+		Metrics:   metrics,
+	})
+}
+
+// indexResources returns the status of each resource per metric keyed by the resource.
+func indexResources(scan report.Scan) map[string][]resourceMetric {
+	index := make(map[string][]resourceMetric)
+	for _, m := range scan.Metrics {
+		excluded := toSet(m.Excluded)
+		unmonitored := toSet(m.Unmonitored)
+		violations := toSet(m.Violations)
+
+		for _, id := range m.Resources {
+			status := report.StatusMonitored
+			if _, ok := excluded[id]; ok {
+				status = report.StatusExcluded
+			} else if _, ok := unmonitored[id]; ok {
+				status = report.StatusUnmonitored
+			}
+
+			_, required := violations[id]
+			index[id] = append(index[id], resourceMetric{
+				Integration: m.Integration,
+				Metric:      m.Metric,
+				Status:      status,
+				Monitors:    m.Monitored[id],
+				Required:    required,
+			})
+		}
+	}
+
+	return index
+}
+
+func writeResponse(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeResponse(w, code, errorResponse{Error: msg})
+}
+
+func toSet(list []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(list))
+	for _, v := range list {
+		set[v] = struct{}{}
+	}
+
+	return set
+}
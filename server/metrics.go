@@ -0,0 +1,131 @@
+package server
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"github.com/terakoya76/modd/report"
+)
+
+// metrics holds the Prometheus metrics of the monitoring coverage and the scans.
+type metrics struct {
+	registry *prometheus.Registry
+
+	unmonitored  *prometheus.GaugeVec
+	resources    *prometheus.GaugeVec
+	unsupported  prometheus.Gauge
+	scanDuration prometheus.Histogram
+	lastScan     prometheus.Gauge
+	scanFailures prometheus.Counter
+	mapperErrors *prometheus.CounterVec
+	scanSuccess  *prometheus.GaugeVec
+
+	// observed holds the metrics set per integration to delete the series of the integrations which are not scanned anymore.
+	observed map[string][]string
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		unmonitored: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "modd_unmonitored_resources",
+			Help: "Number of resources unmonitored for the metric.",
+		}, []string{"integration", "metric"}),
+		resources: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "modd_resources_total",
+			Help: "Number of resources of the integration.",
+		}, []string{"integration"}),
+		unsupported: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "modd_unsupported_metrics",
+			Help: "Number of monitored metrics whose integration is not supported.",
+		}),
+		scanDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "modd_scan_duration_seconds",
+			Help:    "Duration of the successful scans.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}),
+		lastScan: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "modd_last_scan_timestamp_seconds",
+			Help: "Unix time of the last successful scan.",
+		}),
+		scanFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "modd_scan_failures_total",
+			Help: "Number of the scans which failed to be completed.",
+		}),
+		mapperErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "modd_mapper_errors_total",
+			Help: "Number of the scans which failed to fetch the resources of the integration.",
+		}, []string{"integration"}),
+		scanSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "modd_scan_success",
+			Help: "Whether the resources of the integration were fetched in the last successful scan.",
+		}, []string{"integration"}),
+		observed: make(map[string][]string),
+	}
+
+	m.registry.MustRegister(
+		m.unmonitored,
+		m.resources,
+		m.unsupported,
+		m.scanDuration,
+		m.lastScan,
+		m.scanFailures,
+		m.mapperErrors,
+		m.scanSuccess,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// observe updates the metrics with the successful scan.
+// The series of the integrations which are not scanned anymore disappear,
+// and the ones of the integrations which failed to fetch their resources keep the last values.
+func (m *metrics) observe(snapshot Snapshot) {
+	failed := make(map[string]struct{}, len(snapshot.Scan.MapperErrors))
+	for _, it := range snapshot.Scan.MapperErrors {
+		failed[string(it)] = struct{}{}
+		m.mapperErrors.WithLabelValues(string(it)).Inc()
+		m.scanSuccess.WithLabelValues(string(it)).Set(0)
+		if _, ok := m.observed[string(it)]; !ok {
+			m.observed[string(it)] = []string{}
+		}
+	}
+
+	for it, metrics := range m.observed {
+		if _, ok := failed[it]; ok {
+			continue
+		}
+
+		for _, metric := range metrics {
+			m.unmonitored.DeleteLabelValues(it, metric)
+		}
+		m.resources.DeleteLabelValues(it)
+		m.scanSuccess.DeleteLabelValues(it)
+		delete(m.observed, it)
+	}
+
+	for _, mr := range snapshot.Scan.Metrics {
+		it := string(mr.Integration)
+		if _, ok := failed[it]; ok {
+			continue
+		}
+
+		m.unmonitored.WithLabelValues(it, mr.Metric).Set(float64(len(mr.Unmonitored)))
+		m.observed[it] = append(m.observed[it], mr.Metric)
+	}
+
+	for _, ic := range report.BuildCoverageMatrix(snapshot.Scan).Integrations {
+		if _, ok := failed[ic.Integration]; ok {
+			continue
+		}
+
+		m.resources.WithLabelValues(ic.Integration).Set(float64(len(ic.Resources)))
+		m.scanSuccess.WithLabelValues(ic.Integration).Set(1)
+	}
+
+	m.unsupported.Set(float64(len(snapshot.Scan.Unsupported)))
+	m.scanDuration.Observe(snapshot.Duration.Seconds())
+	m.lastScan.Set(float64(snapshot.ScannedAt.Unix()))
+}
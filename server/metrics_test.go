@@ -0,0 +1,160 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+	"github.com/terakoya76/modd/server"
+)
+
+func scrape(t *testing.T, h http.Handler) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		t.Fatalf("failed to scrape metrics: %d\n", rec.Code)
+	}
+
+	b, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %+v\n", err)
+	}
+
+	return string(b)
+}
+
+func Test_Metrics(t *testing.T) {
+	scans := []func() (report.Scan, error){
+		func() (report.Scan, error) {
+			return report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+						},
+					},
+					{
+						Metric:      "aws.sqs.number_of_messages_sent",
+						Integration: datadog.AwsSqs,
+						Result: evaluator.Result{
+							Resources:   []string{"queue-1"},
+							Unmonitored: []string{"queue-1"},
+						},
+					},
+				},
+				Unsupported:  []string{"aws.foo.bar"},
+				MapperErrors: []datadog.IntegrationTarget{datadog.AwsEc2},
+			}, nil
+		},
+		func() (report.Scan, error) {
+			return report.Scan{}, errors.New("boom")
+		},
+		func() (report.Scan, error) {
+			return report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{"db-2"},
+						},
+					},
+				},
+				Unsupported:  []string{"aws.foo.bar"},
+				MapperErrors: []datadog.IntegrationTarget{datadog.AwsSqs},
+			}, nil
+		},
+		func() (report.Scan, error) {
+			return report.Scan{
+				Metrics: []report.MetricResult{
+					{
+						Metric:      "aws.rds.cpuutilization",
+						Integration: datadog.AwsRds,
+						Result: evaluator.Result{
+							Resources:   []string{"db-1", "db-2", "db-3"},
+							Unmonitored: []string{},
+						},
+					},
+				},
+				Unsupported:  []string{},
+				MapperErrors: []datadog.IntegrationTarget{},
+			}, nil
+		},
+	}
+
+	i := 0
+	srv := server.New(func(ctx context.Context) (report.Scan, error) {
+		scan, err := scans[i]()
+		i++
+		return scan, err
+	}, 1)
+	h := srv.Handler()
+
+	// metrics are served before the first scan
+	assert.Contains(t, scrape(t, h), "modd_scan_failures_total 0\n")
+
+	_ = srv.ScanOnce(context.Background())
+	body := scrape(t, h)
+	for _, line := range []string{
+		`modd_unmonitored_resources{integration="aws_rds",metric="aws.rds.cpuutilization"} 1`,
+		`modd_unmonitored_resources{integration="aws_sqs",metric="aws.sqs.number_of_messages_sent"} 1`,
+		`modd_resources_total{integration="aws_rds"} 3`,
+		`modd_resources_total{integration="aws_sqs"} 1`,
+		`modd_unsupported_metrics 1`,
+		`modd_scan_duration_seconds_count 1`,
+		`modd_mapper_errors_total{integration="aws_ec2"} 1`,
+		`modd_scan_success{integration="aws_ec2"} 0`,
+		`modd_scan_success{integration="aws_sqs"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+
+	_ = srv.ScanOnce(context.Background())
+	_ = srv.ScanOnce(context.Background())
+	body = scrape(t, h)
+	for _, line := range []string{
+		`modd_unmonitored_resources{integration="aws_rds",metric="aws.rds.cpuutilization"} 1`,
+		`modd_resources_total{integration="aws_rds"} 3`,
+		`modd_scan_success{integration="aws_rds"} 1`,
+		// the integration which failed to fetch its resources keeps the last values
+		`modd_unmonitored_resources{integration="aws_sqs",metric="aws.sqs.number_of_messages_sent"} 1`,
+		`modd_resources_total{integration="aws_sqs"} 1`,
+		`modd_scan_success{integration="aws_sqs"} 0`,
+		`modd_scan_duration_seconds_count 2`,
+		`modd_scan_failures_total 1`,
+		`modd_mapper_errors_total{integration="aws_ec2"} 1`,
+		`modd_mapper_errors_total{integration="aws_sqs"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, `modd_scan_success{integration="aws_ec2"}`)
+
+	_ = srv.ScanOnce(context.Background())
+	body = scrape(t, h)
+	for _, line := range []string{
+		`modd_unmonitored_resources{integration="aws_rds",metric="aws.rds.cpuutilization"} 0`,
+		`modd_resources_total{integration="aws_rds"} 3`,
+		`modd_unsupported_metrics 0`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+
+	// the integration which is not scanned anymore disappears
+	assert.NotContains(t, body, `modd_unmonitored_resources{integration="aws_sqs"`)
+	assert.NotContains(t, body, `modd_resources_total{integration="aws_sqs"}`)
+	assert.NotContains(t, body, `modd_scan_success{integration="aws_sqs"}`)
+}
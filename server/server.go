@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/terakoya76/modd/report"
+)
+
+// Scanner scans the resources and returns the scan result.
+type Scanner func(ctx context.Context) (report.Scan, error)
+
+// Snapshot represents the latest scan result kept in memory.
+// Duration is the elapsed time of the scan.
+type Snapshot struct {
+	ScannedAt time.Time
+	Duration  time.Duration
+	Scan      report.Scan
+
+	// resources indexes the status of each resource per metric to serve /v1/resources.
+	resources map[string][]resourceMetric
+}
+
+// Server rescans the resources periodically, and serves the latest scan result over HTTP.
+type Server struct {
+	scanner  Scanner
+	interval time.Duration
+	metrics  *metrics
+
+	mu        sync.RWMutex
+	latest    *Snapshot
+	lastError string
+}
+
+// New returns Server which rescans with the scanner at the interval.
+func New(scanner Scanner, interval time.Duration) *Server {
+	return &Server{
+		scanner:  scanner,
+		interval: interval,
+		metrics:  newMetrics(),
+	}
+}
+
+// Run scans immediately and then at the interval until ctx is done.
+// A failed scan keeps the previous result, and is reported via onError when it is not nil.
+func (s *Server) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ScanOnce(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ScanOnce scans the resources, and replaces the latest scan result when it succeeds.
+func (s *Server) ScanOnce(ctx context.Context) error {
+	start := time.Now()
+	scan, err := s.scanner(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.lastError = err.Error()
+		s.metrics.scanFailures.Inc()
+		return fmt.Errorf("failed to scan: %w", err)
+	}
+
+	s.latest = &Snapshot{
+		ScannedAt: start,
+		Duration:  time.Since(start),
+		Scan:      scan,
+		resources: indexResources(scan),
+	}
+	s.lastError = ""
+	s.metrics.observe(*s.latest)
+
+	return nil
+}
+
+// Latest returns the latest scan result, and false when no scan has succeeded yet.
+func (s *Server) Latest() (Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.latest == nil {
+		return Snapshot{}, false
+	}
+
+	return *s.latest, true
+}
+
+// LastError returns the error of the last scan, and empty when it succeeded.
+func (s *Server) LastError() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lastError
+}
@@ -0,0 +1,232 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/terakoya76/modd/datadog"
+	"github.com/terakoya76/modd/evaluator"
+	"github.com/terakoya76/modd/report"
+	"github.com/terakoya76/modd/server"
+)
+
+func get(t *testing.T, h http.Handler, method, path string) (int, map[string]interface{}) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response of %s: %+v\n", path, err)
+	}
+
+	return rec.Code, body
+}
+
+func Test_HandlerBeforeScan(t *testing.T) {
+	srv := server.New(func(ctx context.Context) (report.Scan, error) {
+		return report.Scan{}, errors.New("boom")
+	}, 1)
+
+	code, body := get(t, srv.Handler(), http.MethodGet, "/v1/scan")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "no scan has completed yet", body["Error"])
+
+	assert.NotNil(t, srv.ScanOnce(context.Background()))
+	code, body = get(t, srv.Handler(), http.MethodGet, "/v1/scan")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "boom", body["Error"])
+}
+
+func Test_Handler(t *testing.T) {
+	fail := false
+	srv := server.New(func(ctx context.Context) (report.Scan, error) {
+		if fail {
+			return report.Scan{}, errors.New("boom")
+		}
+		return report.Scan{
+			Metrics: []report.MetricResult{
+				{
+					Metric:      "aws.rds.cpuutilization",
+					Integration: datadog.AwsRds,
+					Result: evaluator.Result{
+						Resources:   []string{"db-1", "db-2", "db-3"},
+						Unmonitored: []string{"db-2"},
+						Excluded:    []string{"db-3"},
+						Monitored:   map[string][]datadog.MonitorRef{"db-1": {{ID: 1, Name: "rds cpu"}}},
+						Violations:  []string{"db-2"},
+					},
+				},
+				{
+					Metric:      "aws.sqs.number_of_messages_sent",
+					Integration: datadog.AwsSqs,
+					Result: evaluator.Result{
+						Resources:   []string{"https://sqs.ap-northeast-1.amazonaws.com/123456789012/queue-1"},
+						Unmonitored: []string{"https://sqs.ap-northeast-1.amazonaws.com/123456789012/queue-1"},
+						Excluded:    []string{},
+						Monitored:   map[string][]datadog.MonitorRef{},
+					},
+				},
+			},
+			Unsupported: []string{"aws.foo.bar"},
+		}, nil
+	}, 1)
+
+	if err := srv.ScanOnce(context.Background()); err != nil {
+		t.Fatalf("failed to scan: %+v\n", err)
+	}
+
+	// a failed rescan keeps the previous result
+	fail = true
+	assert.NotNil(t, srv.ScanOnce(context.Background()))
+
+	h := srv.Handler()
+	cases := []struct {
+		name     string
+		method   string
+		path     string
+		code     int
+		expected map[string]interface{}
+	}{
+		{
+			name:   "when scan is requested",
+			method: http.MethodGet,
+			path:   "/v1/scan",
+			code:   http.StatusOK,
+			expected: map[string]interface{}{
+				"LastError":   "boom",
+				"Unsupported": []interface{}{"aws.foo.bar"},
+			},
+		},
+		{
+			name:   "when integration is requested",
+			method: http.MethodGet,
+			path:   "/v1/integrations/aws_rds",
+			code:   http.StatusOK,
+			expected: map[string]interface{}{
+				"Coverage": map[string]interface{}{
+					"Integration": "aws_rds",
+					"Metrics":     []interface{}{"aws.rds.cpuutilization"},
+					"Coverage":    float64(50),
+				},
+			},
+		},
+		{
+			name:   "when resource is requested",
+			method: http.MethodGet,
+			path:   "/v1/resources/db-2",
+			code:   http.StatusOK,
+			expected: map[string]interface{}{
+				"Resource": "db-2",
+				"Metrics": []interface{}{
+					map[string]interface{}{
+						"Integration": "aws_rds",
+						"Metric":      "aws.rds.cpuutilization",
+						"Status":      "unmonitored",
+						"Monitors":    nil,
+						"Required":    true,
+					},
+				},
+			},
+		},
+		{
+			name:   "when resource is requested by the query",
+			method: http.MethodGet,
+			path:   "/v1/resources?id=https:%2F%2Fsqs.ap-northeast-1.amazonaws.com%2F123456789012%2Fqueue-1",
+			code:   http.StatusOK,
+			expected: map[string]interface{}{
+				"Resource": "https://sqs.ap-northeast-1.amazonaws.com/123456789012/queue-1",
+			},
+		},
+		{
+			name:   "when resource is requested by the escaped path",
+			method: http.MethodGet,
+			path:   "/v1/resources/https:%2F%2Fsqs.ap-northeast-1.amazonaws.com%2F123456789012%2Fqueue-1",
+			code:   http.StatusOK,
+			expected: map[string]interface{}{
+				"Resource": "https://sqs.ap-northeast-1.amazonaws.com/123456789012/queue-1",
+			},
+		},
+		{
+			name:   "when resource containing double slashes is requested by the path",
+			method: http.MethodGet,
+			path:   "/v1/resources/https://sqs.ap-northeast-1.amazonaws.com/123456789012/queue-1",
+			code:   http.StatusOK,
+			expected: map[string]interface{}{
+				"Resource": "https://sqs.ap-northeast-1.amazonaws.com/123456789012/queue-1",
+			},
+		},
+		{
+			name:     "when integration is not found",
+			method:   http.MethodGet,
+			path:     "/v1/integrations/aws_ec2",
+			code:     http.StatusNotFound,
+			expected: map[string]interface{}{"Error": "integration not found: aws_ec2"},
+		},
+		{
+			name:     "when resource is not found",
+			method:   http.MethodGet,
+			path:     "/v1/resources/db-9",
+			code:     http.StatusNotFound,
+			expected: map[string]interface{}{"Error": "resource not found: db-9"},
+		},
+		{
+			name:     "when method is not allowed",
+			method:   http.MethodPost,
+			path:     "/v1/scan",
+			code:     http.StatusMethodNotAllowed,
+			expected: map[string]interface{}{"Error": "method not allowed"},
+		},
+	}
+
+	for _, c := range cases {
+		code, body := get(t, h, c.method, c.path)
+		if !assert.Equal(t, c.code, code) {
+			t.Errorf("case: %s is failed, expected: %d, actual: %d\n", c.name, c.code, code)
+		}
+
+		for k, v := range c.expected {
+			actual := body[k]
+			if m, ok := v.(map[string]interface{}); ok {
+				actualMap, _ := actual.(map[string]interface{})
+				for mk, mv := range m {
+					if !assert.Equal(t, mv, actualMap[mk]) {
+						t.Errorf("case: %s is failed, key: %s.%s\n", c.name, k, mk)
+					}
+				}
+				continue
+			}
+
+			if !assert.Equal(t, v, actual) {
+				t.Errorf("case: %s is failed, key: %s\n", c.name, k)
+			}
+		}
+	}
+}
+
+func Test_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	scans := 0
+	srv := server.New(func(ctx context.Context) (report.Scan, error) {
+		scans++
+		if scans == 3 {
+			cancel()
+		}
+		return report.Scan{Metrics: []report.MetricResult{}}, nil
+	}, time.Millisecond)
+
+	srv.Run(ctx, nil)
+
+	assert.Equal(t, 3, scans)
+	_, ok := srv.Latest()
+	assert.True(t, ok)
+}